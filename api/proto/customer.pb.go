@@ -1,12 +1,13 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.7
+// 	protoc-gen-go v1.36.11
 // 	protoc        v6.32.0
 // source: api/proto/customer.proto
 
 package customerpb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -21,6 +22,61 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CustomerEventType int32
+
+const (
+	CustomerEventType_CUSTOMER_EVENT_TYPE_UNSPECIFIED CustomerEventType = 0
+	CustomerEventType_CUSTOMER_EVENT_TYPE_CREATED     CustomerEventType = 1
+	CustomerEventType_CUSTOMER_EVENT_TYPE_UPDATED     CustomerEventType = 2
+	CustomerEventType_CUSTOMER_EVENT_TYPE_MERGED      CustomerEventType = 3
+	CustomerEventType_CUSTOMER_EVENT_TYPE_ERASED      CustomerEventType = 4
+)
+
+// Enum value maps for CustomerEventType.
+var (
+	CustomerEventType_name = map[int32]string{
+		0: "CUSTOMER_EVENT_TYPE_UNSPECIFIED",
+		1: "CUSTOMER_EVENT_TYPE_CREATED",
+		2: "CUSTOMER_EVENT_TYPE_UPDATED",
+		3: "CUSTOMER_EVENT_TYPE_MERGED",
+		4: "CUSTOMER_EVENT_TYPE_ERASED",
+	}
+	CustomerEventType_value = map[string]int32{
+		"CUSTOMER_EVENT_TYPE_UNSPECIFIED": 0,
+		"CUSTOMER_EVENT_TYPE_CREATED":     1,
+		"CUSTOMER_EVENT_TYPE_UPDATED":     2,
+		"CUSTOMER_EVENT_TYPE_MERGED":      3,
+		"CUSTOMER_EVENT_TYPE_ERASED":      4,
+	}
+)
+
+func (x CustomerEventType) Enum() *CustomerEventType {
+	p := new(CustomerEventType)
+	*p = x
+	return p
+}
+
+func (x CustomerEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CustomerEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_customer_proto_enumTypes[0].Descriptor()
+}
+
+func (CustomerEventType) Type() protoreflect.EnumType {
+	return &file_api_proto_customer_proto_enumTypes[0]
+}
+
+func (x CustomerEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CustomerEventType.Descriptor instead.
+func (CustomerEventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_customer_proto_rawDescGZIP(), []int{0}
+}
+
 type UpsertCustomerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Idn           string                 `protobuf:"bytes,1,opt,name=idn,proto3" json:"idn,omitempty"`
@@ -169,11 +225,270 @@ func (x *CustomerResponse) GetCreatedAt() string {
 	return ""
 }
 
+type EraseCustomerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Idn           string                 `protobuf:"bytes,1,opt,name=idn,proto3" json:"idn,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseCustomerRequest) Reset() {
+	*x = EraseCustomerRequest{}
+	mi := &file_api_proto_customer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseCustomerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseCustomerRequest) ProtoMessage() {}
+
+func (x *EraseCustomerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_customer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseCustomerRequest.ProtoReflect.Descriptor instead.
+func (*EraseCustomerRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_customer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EraseCustomerRequest) GetIdn() string {
+	if x != nil {
+		return x.Idn
+	}
+	return ""
+}
+
+func (x *EraseCustomerRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type EraseCustomerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ErasedAt      string                 `protobuf:"bytes,2,opt,name=erased_at,json=erasedAt,proto3" json:"erased_at,omitempty"`
+	ErasureHash   string                 `protobuf:"bytes,3,opt,name=erasure_hash,json=erasureHash,proto3" json:"erasure_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseCustomerResponse) Reset() {
+	*x = EraseCustomerResponse{}
+	mi := &file_api_proto_customer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseCustomerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseCustomerResponse) ProtoMessage() {}
+
+func (x *EraseCustomerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_customer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseCustomerResponse.ProtoReflect.Descriptor instead.
+func (*EraseCustomerResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_customer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EraseCustomerResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EraseCustomerResponse) GetErasedAt() string {
+	if x != nil {
+		return x.ErasedAt
+	}
+	return ""
+}
+
+func (x *EraseCustomerResponse) GetErasureHash() string {
+	if x != nil {
+		return x.ErasureHash
+	}
+	return ""
+}
+
+type WatchCustomersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resume after this cursor; 0 replays the whole change log.
+	Cursor int64 `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Ignore cursor and only stream changes made after the call.
+	FromLatest    bool `protobuf:"varint,2,opt,name=from_latest,json=fromLatest,proto3" json:"from_latest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchCustomersRequest) Reset() {
+	*x = WatchCustomersRequest{}
+	mi := &file_api_proto_customer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchCustomersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCustomersRequest) ProtoMessage() {}
+
+func (x *WatchCustomersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_customer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCustomersRequest.ProtoReflect.Descriptor instead.
+func (*WatchCustomersRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_customer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WatchCustomersRequest) GetCursor() int64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *WatchCustomersRequest) GetFromLatest() bool {
+	if x != nil {
+		return x.FromLatest
+	}
+	return false
+}
+
+type CustomerEvent struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Cursor     int64                  `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Type       CustomerEventType      `protobuf:"varint,2,opt,name=type,proto3,enum=customer.CustomerEventType" json:"type,omitempty"`
+	CustomerId string                 `protobuf:"bytes,3,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	// Empty once the customer has been erased.
+	Idn           string `protobuf:"bytes,4,opt,name=idn,proto3" json:"idn,omitempty"`
+	MergedIntoId  string `protobuf:"bytes,5,opt,name=merged_into_id,json=mergedIntoId,proto3" json:"merged_into_id,omitempty"`
+	OccurredAt    string `protobuf:"bytes,6,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	TenantId      string `protobuf:"bytes,7,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomerEvent) Reset() {
+	*x = CustomerEvent{}
+	mi := &file_api_proto_customer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomerEvent) ProtoMessage() {}
+
+func (x *CustomerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_customer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomerEvent.ProtoReflect.Descriptor instead.
+func (*CustomerEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_customer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CustomerEvent) GetCursor() int64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *CustomerEvent) GetType() CustomerEventType {
+	if x != nil {
+		return x.Type
+	}
+	return CustomerEventType_CUSTOMER_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *CustomerEvent) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *CustomerEvent) GetIdn() string {
+	if x != nil {
+		return x.Idn
+	}
+	return ""
+}
+
+func (x *CustomerEvent) GetMergedIntoId() string {
+	if x != nil {
+		return x.MergedIntoId
+	}
+	return ""
+}
+
+func (x *CustomerEvent) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+func (x *CustomerEvent) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
 var File_api_proto_customer_proto protoreflect.FileDescriptor
 
 const file_api_proto_customer_proto_rawDesc = "" +
 	"\n" +
-	"\x18api/proto/customer.proto\x12\bcustomer\")\n" +
+	"\x18api/proto/customer.proto\x12\bcustomer\x1a\x1cgoogle/api/annotations.proto\")\n" +
 	"\x15UpsertCustomerRequest\x12\x10\n" +
 	"\x03idn\x18\x01 \x01(\tR\x03idn\"&\n" +
 	"\x12GetCustomerRequest\x12\x10\n" +
@@ -182,10 +497,39 @@ const file_api_proto_customer_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03idn\x18\x02 \x01(\tR\x03idn\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x03 \x01(\tR\tcreatedAt2\xa9\x01\n" +
-	"\x0fCustomerService\x12M\n" +
-	"\x0eUpsertCustomer\x12\x1f.customer.UpsertCustomerRequest\x1a\x1a.customer.CustomerResponse\x12G\n" +
-	"\vGetCustomer\x12\x1c.customer.GetCustomerRequest\x1a\x1a.customer.CustomerResponseB0Z.shipment-customer-service/api/proto;customerpbb\x06proto3"
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\"@\n" +
+	"\x14EraseCustomerRequest\x12\x10\n" +
+	"\x03idn\x18\x01 \x01(\tR\x03idn\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"g\n" +
+	"\x15EraseCustomerResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\terased_at\x18\x02 \x01(\tR\berasedAt\x12!\n" +
+	"\ferasure_hash\x18\x03 \x01(\tR\verasureHash\"P\n" +
+	"\x15WatchCustomersRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\x03R\x06cursor\x12\x1f\n" +
+	"\vfrom_latest\x18\x02 \x01(\bR\n" +
+	"fromLatest\"\xef\x01\n" +
+	"\rCustomerEvent\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\x03R\x06cursor\x12/\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1b.customer.CustomerEventTypeR\x04type\x12\x1f\n" +
+	"\vcustomer_id\x18\x03 \x01(\tR\n" +
+	"customerId\x12\x10\n" +
+	"\x03idn\x18\x04 \x01(\tR\x03idn\x12$\n" +
+	"\x0emerged_into_id\x18\x05 \x01(\tR\fmergedIntoId\x12\x1f\n" +
+	"\voccurred_at\x18\x06 \x01(\tR\n" +
+	"occurredAt\x12\x1b\n" +
+	"\ttenant_id\x18\a \x01(\tR\btenantId*\xba\x01\n" +
+	"\x11CustomerEventType\x12#\n" +
+	"\x1fCUSTOMER_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bCUSTOMER_EVENT_TYPE_CREATED\x10\x01\x12\x1f\n" +
+	"\x1bCUSTOMER_EVENT_TYPE_UPDATED\x10\x02\x12\x1e\n" +
+	"\x1aCUSTOMER_EVENT_TYPE_MERGED\x10\x03\x12\x1e\n" +
+	"\x1aCUSTOMER_EVENT_TYPE_ERASED\x10\x042\xd1\x03\n" +
+	"\x0fCustomerService\x12k\n" +
+	"\x0eUpsertCustomer\x12\x1f.customer.UpsertCustomerRequest\x1a\x1a.customer.CustomerResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/api/v2/customers\x12l\n" +
+	"\vGetCustomer\x12\x1c.customer.GetCustomerRequest\x1a\x1a.customer.CustomerResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/api/v2/customers/lookup\x12t\n" +
+	"\rEraseCustomer\x12\x1e.customer.EraseCustomerRequest\x1a\x1f.customer.EraseCustomerResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/api/v2/customers/erase\x12m\n" +
+	"\x0eWatchCustomers\x12\x1f.customer.WatchCustomersRequest\x1a\x17.customer.CustomerEvent\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/api/v2/customer-events0\x01B0Z.shipment-customer-service/api/proto;customerpbb\x06proto3"
 
 var (
 	file_api_proto_customer_proto_rawDescOnce sync.Once
@@ -199,22 +543,33 @@ func file_api_proto_customer_proto_rawDescGZIP() []byte {
 	return file_api_proto_customer_proto_rawDescData
 }
 
-var file_api_proto_customer_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_proto_customer_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_proto_customer_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_api_proto_customer_proto_goTypes = []any{
-	(*UpsertCustomerRequest)(nil), // 0: customer.UpsertCustomerRequest
-	(*GetCustomerRequest)(nil),    // 1: customer.GetCustomerRequest
-	(*CustomerResponse)(nil),      // 2: customer.CustomerResponse
+	(CustomerEventType)(0),        // 0: customer.CustomerEventType
+	(*UpsertCustomerRequest)(nil), // 1: customer.UpsertCustomerRequest
+	(*GetCustomerRequest)(nil),    // 2: customer.GetCustomerRequest
+	(*CustomerResponse)(nil),      // 3: customer.CustomerResponse
+	(*EraseCustomerRequest)(nil),  // 4: customer.EraseCustomerRequest
+	(*EraseCustomerResponse)(nil), // 5: customer.EraseCustomerResponse
+	(*WatchCustomersRequest)(nil), // 6: customer.WatchCustomersRequest
+	(*CustomerEvent)(nil),         // 7: customer.CustomerEvent
 }
 var file_api_proto_customer_proto_depIdxs = []int32{
-	0, // 0: customer.CustomerService.UpsertCustomer:input_type -> customer.UpsertCustomerRequest
-	1, // 1: customer.CustomerService.GetCustomer:input_type -> customer.GetCustomerRequest
-	2, // 2: customer.CustomerService.UpsertCustomer:output_type -> customer.CustomerResponse
-	2, // 3: customer.CustomerService.GetCustomer:output_type -> customer.CustomerResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: customer.CustomerEvent.type:type_name -> customer.CustomerEventType
+	1, // 1: customer.CustomerService.UpsertCustomer:input_type -> customer.UpsertCustomerRequest
+	2, // 2: customer.CustomerService.GetCustomer:input_type -> customer.GetCustomerRequest
+	4, // 3: customer.CustomerService.EraseCustomer:input_type -> customer.EraseCustomerRequest
+	6, // 4: customer.CustomerService.WatchCustomers:input_type -> customer.WatchCustomersRequest
+	3, // 5: customer.CustomerService.UpsertCustomer:output_type -> customer.CustomerResponse
+	3, // 6: customer.CustomerService.GetCustomer:output_type -> customer.CustomerResponse
+	5, // 7: customer.CustomerService.EraseCustomer:output_type -> customer.EraseCustomerResponse
+	7, // 8: customer.CustomerService.WatchCustomers:output_type -> customer.CustomerEvent
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_customer_proto_init() }
@@ -227,13 +582,14 @@ func file_api_proto_customer_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_customer_proto_rawDesc), len(file_api_proto_customer_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   3,
+			NumEnums:      1,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_proto_customer_proto_goTypes,
 		DependencyIndexes: file_api_proto_customer_proto_depIdxs,
+		EnumInfos:         file_api_proto_customer_proto_enumTypes,
 		MessageInfos:      file_api_proto_customer_proto_msgTypes,
 	}.Build()
 	File_api_proto_customer_proto = out.File
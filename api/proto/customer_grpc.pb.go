@@ -21,14 +21,21 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	CustomerService_UpsertCustomer_FullMethodName = "/customer.CustomerService/UpsertCustomer"
 	CustomerService_GetCustomer_FullMethodName    = "/customer.CustomerService/GetCustomer"
+	CustomerService_EraseCustomer_FullMethodName  = "/customer.CustomerService/EraseCustomer"
+	CustomerService_WatchCustomers_FullMethodName = "/customer.CustomerService/WatchCustomers"
 )
 
 // CustomerServiceClient is the client API for CustomerService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The REST mapping is for admin tooling. IDNs travel in request bodies only,
+// never in URLs, so they do not end up in access logs.
 type CustomerServiceClient interface {
 	UpsertCustomer(ctx context.Context, in *UpsertCustomerRequest, opts ...grpc.CallOption) (*CustomerResponse, error)
 	GetCustomer(ctx context.Context, in *GetCustomerRequest, opts ...grpc.CallOption) (*CustomerResponse, error)
+	EraseCustomer(ctx context.Context, in *EraseCustomerRequest, opts ...grpc.CallOption) (*EraseCustomerResponse, error)
+	WatchCustomers(ctx context.Context, in *WatchCustomersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CustomerEvent], error)
 }
 
 type customerServiceClient struct {
@@ -59,12 +66,46 @@ func (c *customerServiceClient) GetCustomer(ctx context.Context, in *GetCustomer
 	return out, nil
 }
 
+func (c *customerServiceClient) EraseCustomer(ctx context.Context, in *EraseCustomerRequest, opts ...grpc.CallOption) (*EraseCustomerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EraseCustomerResponse)
+	err := c.cc.Invoke(ctx, CustomerService_EraseCustomer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerServiceClient) WatchCustomers(ctx context.Context, in *WatchCustomersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CustomerEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CustomerService_ServiceDesc.Streams[0], CustomerService_WatchCustomers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchCustomersRequest, CustomerEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_WatchCustomersClient = grpc.ServerStreamingClient[CustomerEvent]
+
 // CustomerServiceServer is the server API for CustomerService service.
 // All implementations must embed UnimplementedCustomerServiceServer
 // for forward compatibility.
+//
+// The REST mapping is for admin tooling. IDNs travel in request bodies only,
+// never in URLs, so they do not end up in access logs.
 type CustomerServiceServer interface {
 	UpsertCustomer(context.Context, *UpsertCustomerRequest) (*CustomerResponse, error)
 	GetCustomer(context.Context, *GetCustomerRequest) (*CustomerResponse, error)
+	EraseCustomer(context.Context, *EraseCustomerRequest) (*EraseCustomerResponse, error)
+	WatchCustomers(*WatchCustomersRequest, grpc.ServerStreamingServer[CustomerEvent]) error
 	mustEmbedUnimplementedCustomerServiceServer()
 }
 
@@ -81,6 +122,12 @@ func (UnimplementedCustomerServiceServer) UpsertCustomer(context.Context, *Upser
 func (UnimplementedCustomerServiceServer) GetCustomer(context.Context, *GetCustomerRequest) (*CustomerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCustomer not implemented")
 }
+func (UnimplementedCustomerServiceServer) EraseCustomer(context.Context, *EraseCustomerRequest) (*EraseCustomerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseCustomer not implemented")
+}
+func (UnimplementedCustomerServiceServer) WatchCustomers(*WatchCustomersRequest, grpc.ServerStreamingServer[CustomerEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCustomers not implemented")
+}
 func (UnimplementedCustomerServiceServer) mustEmbedUnimplementedCustomerServiceServer() {}
 func (UnimplementedCustomerServiceServer) testEmbeddedByValue()                         {}
 
@@ -138,6 +185,35 @@ func _CustomerService_GetCustomer_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CustomerService_EraseCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).EraseCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_EraseCustomer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).EraseCustomer(ctx, req.(*EraseCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomerService_WatchCustomers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCustomersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CustomerServiceServer).WatchCustomers(m, &grpc.GenericServerStream[WatchCustomersRequest, CustomerEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CustomerService_WatchCustomersServer = grpc.ServerStreamingServer[CustomerEvent]
+
 // CustomerService_ServiceDesc is the grpc.ServiceDesc for CustomerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,7 +229,17 @@ var CustomerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCustomer",
 			Handler:    _CustomerService_GetCustomer_Handler,
 		},
+		{
+			MethodName: "EraseCustomer",
+			Handler:    _CustomerService_EraseCustomer_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCustomers",
+			Handler:       _CustomerService_WatchCustomers_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/customer.proto",
 }
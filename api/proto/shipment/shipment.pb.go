@@ -0,0 +1,788 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.32.0
+// source: api/proto/shipment/shipment.proto
+
+package shipmentpb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Shipment struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Route  string                 `protobuf:"bytes,2,opt,name=route,proto3" json:"route,omitempty"`
+	Price  float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Status string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// Empty while the shipment is PENDING_CUSTOMER.
+	CustomerId    string `protobuf:"bytes,5,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	CreatedAt     string `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Shipment) Reset() {
+	*x = Shipment{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Shipment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Shipment) ProtoMessage() {}
+
+func (x *Shipment) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Shipment.ProtoReflect.Descriptor instead.
+func (*Shipment) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Shipment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Shipment) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+func (x *Shipment) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Shipment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Shipment) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *Shipment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateShipmentRequest struct {
+	state         protoimpl.MessageState          `protogen:"open.v1"`
+	Route         string                          `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	Price         float64                         `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Customer      *CreateShipmentRequest_Customer `protobuf:"bytes,3,opt,name=customer,proto3" json:"customer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShipmentRequest) Reset() {
+	*x = CreateShipmentRequest{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShipmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShipmentRequest) ProtoMessage() {}
+
+func (x *CreateShipmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShipmentRequest.ProtoReflect.Descriptor instead.
+func (*CreateShipmentRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateShipmentRequest) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+func (x *CreateShipmentRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateShipmentRequest) GetCustomer() *CreateShipmentRequest_Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+type GetShipmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShipmentRequest) Reset() {
+	*x = GetShipmentRequest{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShipmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShipmentRequest) ProtoMessage() {}
+
+func (x *GetShipmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShipmentRequest.ProtoReflect.Descriptor instead.
+func (*GetShipmentRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetShipmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListShipmentsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Status     string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Defaults to 50, at most 100.
+	PageSize      int32  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListShipmentsRequest) Reset() {
+	*x = ListShipmentsRequest{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListShipmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListShipmentsRequest) ProtoMessage() {}
+
+func (x *ListShipmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListShipmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListShipmentsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListShipmentsRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *ListShipmentsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListShipmentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListShipmentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListShipmentsResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Shipments []*Shipment            `protobuf:"bytes,1,rep,name=shipments,proto3" json:"shipments,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListShipmentsResponse) Reset() {
+	*x = ListShipmentsResponse{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListShipmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListShipmentsResponse) ProtoMessage() {}
+
+func (x *ListShipmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListShipmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListShipmentsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListShipmentsResponse) GetShipments() []*Shipment {
+	if x != nil {
+		return x.Shipments
+	}
+	return nil
+}
+
+func (x *ListShipmentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type CancelShipmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelShipmentRequest) Reset() {
+	*x = CancelShipmentRequest{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelShipmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelShipmentRequest) ProtoMessage() {}
+
+func (x *CancelShipmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelShipmentRequest.ProtoReflect.Descriptor instead.
+func (*CancelShipmentRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CancelShipmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type TransitionShipmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitionShipmentRequest) Reset() {
+	*x = TransitionShipmentRequest{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionShipmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionShipmentRequest) ProtoMessage() {}
+
+func (x *TransitionShipmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionShipmentRequest.ProtoReflect.Descriptor instead.
+func (*TransitionShipmentRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TransitionShipmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TransitionShipmentRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type WatchShipmentsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ShipmentId string                 `protobuf:"bytes,1,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	CustomerId string                 `protobuf:"bytes,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	// Replay stored events after this cursor first; 0 streams new events only.
+	Cursor        int64 `protobuf:"varint,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchShipmentsRequest) Reset() {
+	*x = WatchShipmentsRequest{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchShipmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchShipmentsRequest) ProtoMessage() {}
+
+func (x *WatchShipmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchShipmentsRequest.ProtoReflect.Descriptor instead.
+func (*WatchShipmentsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchShipmentsRequest) GetShipmentId() string {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return ""
+}
+
+func (x *WatchShipmentsRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *WatchShipmentsRequest) GetCursor() int64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+type ShipmentEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Cursor         int64                  `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	EventId        string                 `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Type           string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	ShipmentId     string                 `protobuf:"bytes,4,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	CustomerId     string                 `protobuf:"bytes,5,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Route          string                 `protobuf:"bytes,6,opt,name=route,proto3" json:"route,omitempty"`
+	Price          float64                `protobuf:"fixed64,7,opt,name=price,proto3" json:"price,omitempty"`
+	Status         string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	PreviousStatus string                 `protobuf:"bytes,9,opt,name=previous_status,json=previousStatus,proto3" json:"previous_status,omitempty"`
+	OccurredAt     string                 `protobuf:"bytes,10,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ShipmentEvent) Reset() {
+	*x = ShipmentEvent{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipmentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipmentEvent) ProtoMessage() {}
+
+func (x *ShipmentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipmentEvent.ProtoReflect.Descriptor instead.
+func (*ShipmentEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ShipmentEvent) GetCursor() int64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *ShipmentEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ShipmentEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ShipmentEvent) GetShipmentId() string {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return ""
+}
+
+func (x *ShipmentEvent) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *ShipmentEvent) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+func (x *ShipmentEvent) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ShipmentEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ShipmentEvent) GetPreviousStatus() string {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return ""
+}
+
+func (x *ShipmentEvent) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+type CreateShipmentRequest_Customer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Idn           string                 `protobuf:"bytes,1,opt,name=idn,proto3" json:"idn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateShipmentRequest_Customer) Reset() {
+	*x = CreateShipmentRequest_Customer{}
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateShipmentRequest_Customer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateShipmentRequest_Customer) ProtoMessage() {}
+
+func (x *CreateShipmentRequest_Customer) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_shipment_shipment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateShipmentRequest_Customer.ProtoReflect.Descriptor instead.
+func (*CreateShipmentRequest_Customer) Descriptor() ([]byte, []int) {
+	return file_api_proto_shipment_shipment_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *CreateShipmentRequest_Customer) GetIdn() string {
+	if x != nil {
+		return x.Idn
+	}
+	return ""
+}
+
+var File_api_proto_shipment_shipment_proto protoreflect.FileDescriptor
+
+const file_api_proto_shipment_shipment_proto_rawDesc = "" +
+	"\n" +
+	"!api/proto/shipment/shipment.proto\x12\bshipment\x1a\x1cgoogle/api/annotations.proto\"\x9e\x01\n" +
+	"\bShipment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05route\x18\x02 \x01(\tR\x05route\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1f\n" +
+	"\vcustomer_id\x18\x05 \x01(\tR\n" +
+	"customerId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\"\xa7\x01\n" +
+	"\x15CreateShipmentRequest\x12\x14\n" +
+	"\x05route\x18\x01 \x01(\tR\x05route\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12D\n" +
+	"\bcustomer\x18\x03 \x01(\v2(.shipment.CreateShipmentRequest.CustomerR\bcustomer\x1a\x1c\n" +
+	"\bCustomer\x12\x10\n" +
+	"\x03idn\x18\x01 \x01(\tR\x03idn\"$\n" +
+	"\x12GetShipmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8b\x01\n" +
+	"\x14ListShipmentsRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"q\n" +
+	"\x15ListShipmentsResponse\x120\n" +
+	"\tshipments\x18\x01 \x03(\v2\x12.shipment.ShipmentR\tshipments\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"'\n" +
+	"\x15CancelShipmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"C\n" +
+	"\x19TransitionShipmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"q\n" +
+	"\x15WatchShipmentsRequest\x12\x1f\n" +
+	"\vshipment_id\x18\x01 \x01(\tR\n" +
+	"shipmentId\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\tR\n" +
+	"customerId\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\x03R\x06cursor\"\xa6\x02\n" +
+	"\rShipmentEvent\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\x03R\x06cursor\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\tR\aeventId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x1f\n" +
+	"\vshipment_id\x18\x04 \x01(\tR\n" +
+	"shipmentId\x12\x1f\n" +
+	"\vcustomer_id\x18\x05 \x01(\tR\n" +
+	"customerId\x12\x14\n" +
+	"\x05route\x18\x06 \x01(\tR\x05route\x12\x14\n" +
+	"\x05price\x18\a \x01(\x01R\x05price\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12'\n" +
+	"\x0fprevious_status\x18\t \x01(\tR\x0epreviousStatus\x12\x1f\n" +
+	"\voccurred_at\x18\n" +
+	" \x01(\tR\n" +
+	"occurredAt2\x9d\x05\n" +
+	"\x0fShipmentService\x12c\n" +
+	"\x0eCreateShipment\x12\x1f.shipment.CreateShipmentRequest\x1a\x12.shipment.Shipment\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/api/v2/shipments\x12_\n" +
+	"\vGetShipment\x12\x1c.shipment.GetShipmentRequest\x1a\x12.shipment.Shipment\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/v2/shipments/{id}\x12k\n" +
+	"\rListShipments\x12\x1e.shipment.ListShipmentsRequest\x1a\x1f.shipment.ListShipmentsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/api/v2/shipments\x12o\n" +
+	"\x0eCancelShipment\x12\x1f.shipment.CancelShipmentRequest\x1a\x12.shipment.Shipment\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/v2/shipments/{id}/cancel\x12w\n" +
+	"\x12TransitionShipment\x12#.shipment.TransitionShipmentRequest\x1a\x12.shipment.Shipment\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/v2/shipments/{id}/status\x12m\n" +
+	"\x0eWatchShipments\x12\x1f.shipment.WatchShipmentsRequest\x1a\x17.shipment.ShipmentEvent\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/api/v2/shipment-events0\x01B9Z7shipment-customer-service/api/proto/shipment;shipmentpbb\x06proto3"
+
+var (
+	file_api_proto_shipment_shipment_proto_rawDescOnce sync.Once
+	file_api_proto_shipment_shipment_proto_rawDescData []byte
+)
+
+func file_api_proto_shipment_shipment_proto_rawDescGZIP() []byte {
+	file_api_proto_shipment_shipment_proto_rawDescOnce.Do(func() {
+		file_api_proto_shipment_shipment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_shipment_shipment_proto_rawDesc), len(file_api_proto_shipment_shipment_proto_rawDesc)))
+	})
+	return file_api_proto_shipment_shipment_proto_rawDescData
+}
+
+var file_api_proto_shipment_shipment_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_proto_shipment_shipment_proto_goTypes = []any{
+	(*Shipment)(nil),                       // 0: shipment.Shipment
+	(*CreateShipmentRequest)(nil),          // 1: shipment.CreateShipmentRequest
+	(*GetShipmentRequest)(nil),             // 2: shipment.GetShipmentRequest
+	(*ListShipmentsRequest)(nil),           // 3: shipment.ListShipmentsRequest
+	(*ListShipmentsResponse)(nil),          // 4: shipment.ListShipmentsResponse
+	(*CancelShipmentRequest)(nil),          // 5: shipment.CancelShipmentRequest
+	(*TransitionShipmentRequest)(nil),      // 6: shipment.TransitionShipmentRequest
+	(*WatchShipmentsRequest)(nil),          // 7: shipment.WatchShipmentsRequest
+	(*ShipmentEvent)(nil),                  // 8: shipment.ShipmentEvent
+	(*CreateShipmentRequest_Customer)(nil), // 9: shipment.CreateShipmentRequest.Customer
+}
+var file_api_proto_shipment_shipment_proto_depIdxs = []int32{
+	9, // 0: shipment.CreateShipmentRequest.customer:type_name -> shipment.CreateShipmentRequest.Customer
+	0, // 1: shipment.ListShipmentsResponse.shipments:type_name -> shipment.Shipment
+	1, // 2: shipment.ShipmentService.CreateShipment:input_type -> shipment.CreateShipmentRequest
+	2, // 3: shipment.ShipmentService.GetShipment:input_type -> shipment.GetShipmentRequest
+	3, // 4: shipment.ShipmentService.ListShipments:input_type -> shipment.ListShipmentsRequest
+	5, // 5: shipment.ShipmentService.CancelShipment:input_type -> shipment.CancelShipmentRequest
+	6, // 6: shipment.ShipmentService.TransitionShipment:input_type -> shipment.TransitionShipmentRequest
+	7, // 7: shipment.ShipmentService.WatchShipments:input_type -> shipment.WatchShipmentsRequest
+	0, // 8: shipment.ShipmentService.CreateShipment:output_type -> shipment.Shipment
+	0, // 9: shipment.ShipmentService.GetShipment:output_type -> shipment.Shipment
+	4, // 10: shipment.ShipmentService.ListShipments:output_type -> shipment.ListShipmentsResponse
+	0, // 11: shipment.ShipmentService.CancelShipment:output_type -> shipment.Shipment
+	0, // 12: shipment.ShipmentService.TransitionShipment:output_type -> shipment.Shipment
+	8, // 13: shipment.ShipmentService.WatchShipments:output_type -> shipment.ShipmentEvent
+	8, // [8:14] is the sub-list for method output_type
+	2, // [2:8] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_shipment_shipment_proto_init() }
+func file_api_proto_shipment_shipment_proto_init() {
+	if File_api_proto_shipment_shipment_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_shipment_shipment_proto_rawDesc), len(file_api_proto_shipment_shipment_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_proto_shipment_shipment_proto_goTypes,
+		DependencyIndexes: file_api_proto_shipment_shipment_proto_depIdxs,
+		MessageInfos:      file_api_proto_shipment_shipment_proto_msgTypes,
+	}.Build()
+	File_api_proto_shipment_shipment_proto = out.File
+	file_api_proto_shipment_shipment_proto_goTypes = nil
+	file_api_proto_shipment_shipment_proto_depIdxs = nil
+}
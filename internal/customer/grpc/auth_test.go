@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	customerpb "shipment-customer-service/api/proto"
+	"shipment-customer-service/internal/platform/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+// testTokens returns a verifier and a function signing tokens it accepts.
+// The claims passed in are added to those of the shipment service.
+func testTokens(t *testing.T) (*auth.Verifier, func(claims map[string]any) string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kid": "k1", "kty": "RSA",
+		"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	verifier, err := auth.NewVerifier(auth.NewKeySet(path, time.Hour), auth.VerifierConfig{Issuer: "sso", Audience: "customers"})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	sign := func(extra map[string]any) string {
+		header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1"})
+		claims := map[string]any{
+			"iss": "sso", "aud": "customers", "sub": "shipment-service",
+			"exp": time.Now().Add(time.Minute).Unix(),
+		}
+		for name, value := range extra {
+			claims[name] = value
+		}
+		payload, _ := json.Marshal(claims)
+		input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+		digest := crypto.SHA256.New()
+		digest.Write([]byte(input))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest.Sum(nil))
+		if err != nil {
+			t.Fatalf("SignPKCS1v15() error = %v", err)
+		}
+		return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+	return verifier, sign
+}
+
+func TestAuthenticatorUnary(t *testing.T) {
+	verifier, signClaims := testTokens(t)
+	sign := func(scope string) string { return signClaims(map[string]any{"scope": scope}) }
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		want   codes.Code
+	}{
+		{name: "missing token", method: customerpb.CustomerService_GetCustomer_FullMethodName, want: codes.Unauthenticated},
+		{name: "invalid token", method: customerpb.CustomerService_GetCustomer_FullMethodName, token: "a.b.c", want: codes.Unauthenticated},
+		{name: "granted", method: customerpb.CustomerService_UpsertCustomer_FullMethodName, token: sign("customers:read customers:write"), want: codes.OK},
+		{name: "missing scope", method: customerpb.CustomerService_EraseCustomer_FullMethodName, token: sign("customers:read customers:write"), want: codes.PermissionDenied},
+		{name: "unlisted method", method: "/customer.CustomerService/DropTable", token: sign("admin"), want: codes.PermissionDenied},
+		{name: "public health check", method: healthpb.Health_Check_FullMethodName, want: codes.OK},
+		{name: "reflection needs token", method: reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName, want: codes.Unauthenticated},
+		{name: "reflection granted", method: reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName, token: sign("customers:read"), want: codes.OK},
+	}
+
+	interceptor := NewAuthenticator(verifier, slog.New(slog.NewTextHandler(io.Discard, nil))).Unary()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+tt.token))
+			}
+
+			var principal string
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				principal = auth.PrincipalID(ctx)
+				return nil, nil
+			})
+
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("interceptor code = %v, want %v", got, tt.want)
+			}
+			if tt.want == codes.OK && tt.token != "" && principal != "user:shipment-service" {
+				t.Fatalf("interceptor principal = %q, want user:shipment-service", principal)
+			}
+		})
+	}
+}
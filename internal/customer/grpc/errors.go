@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"shipment-customer-service/internal/customer/service"
+	domain "shipment-customer-service/internal/domain/customer"
+	"shipment-customer-service/internal/platform/i18n"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError builds a gRPC error whose message stays English for logs and
+// existing clients. Details carry the stable reason and the message in the
+// language negotiated from the call's accept-language metadata.
+func statusError(ctx context.Context, grpcCode codes.Code, code string) error {
+	lang := i18n.FromIncomingContext(ctx)
+	st, err := status.New(grpcCode, domain.ErrorMessages.Message(code, i18n.English)).WithDetails(
+		&errdetails.ErrorInfo{Reason: code, Domain: domain.ErrorDomain},
+		&errdetails.LocalizedMessage{Locale: string(lang), Message: domain.ErrorMessages.Message(code, lang)},
+	)
+	if err != nil {
+		return status.Error(grpcCode, domain.ErrorMessages.Message(code, i18n.English))
+	}
+	return st.Err()
+}
+
+func mapError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidIDN):
+		return statusError(ctx, codes.InvalidArgument, domain.CodeInvalidIDN)
+	case errors.Is(err, service.ErrNotFound):
+		return statusError(ctx, codes.NotFound, domain.CodeCustomerNotFound)
+	default:
+		return statusError(ctx, codes.Internal, domain.CodeInternal)
+	}
+}
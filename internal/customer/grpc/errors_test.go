@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"shipment-customer-service/internal/customer/service"
+	domain "shipment-customer-service/internal/domain/customer"
+	"shipment-customer-service/internal/platform/i18n"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMapErrorLocalizesMessage(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "kk-KZ"))
+
+	st := status.Convert(mapError(ctx, service.ErrNotFound))
+
+	if st.Code() != codes.NotFound {
+		t.Fatalf("mapError() code = %v, want %v", st.Code(), codes.NotFound)
+	}
+	var gotReason string
+	var gotMessage *errdetails.LocalizedMessage
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			gotReason = detail.GetReason()
+		case *errdetails.LocalizedMessage:
+			gotMessage = detail
+		}
+	}
+	if gotReason != domain.CodeCustomerNotFound {
+		t.Fatalf("mapError() reason = %q, want %q", gotReason, domain.CodeCustomerNotFound)
+	}
+	if gotMessage.GetLocale() != string(i18n.Kazakh) || gotMessage.GetMessage() != domain.ErrorMessages.Message(domain.CodeCustomerNotFound, i18n.Kazakh) {
+		t.Fatalf("mapError() localized message = %v, want the Kazakh one", gotMessage)
+	}
+}
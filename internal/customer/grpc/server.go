@@ -2,16 +2,15 @@ package grpc
 
 import (
 	"context"
-	"errors"
 	"log/slog"
 	"time"
 
 	customerpb "shipment-customer-service/api/proto"
 	"shipment-customer-service/internal/customer/service"
+	domain "shipment-customer-service/internal/domain/customer"
 	"shipment-customer-service/internal/platform/telemetry"
 
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 type Server struct {
@@ -26,17 +25,17 @@ func NewServer(service *service.Service, logger *slog.Logger) *Server {
 
 func (s *Server) UpsertCustomer(ctx context.Context, req *customerpb.UpsertCustomerRequest) (*customerpb.CustomerResponse, error) {
 	if req == nil {
-		return nil, status.Error(codes.InvalidArgument, "request is required")
+		return nil, statusError(ctx, codes.InvalidArgument, domain.CodeInvalidRequest)
 	}
 
 	customer, err := s.service.UpsertCustomer(ctx, req.GetIdn())
 	if err != nil {
-		return nil, mapError(err)
+		return nil, mapError(ctx, err)
 	}
 
 	s.logger.Info(
 		"upsert_customer",
-		slog.String("idn", req.GetIdn()),
+		slog.String("idn", telemetry.MaskIDN(req.GetIdn())),
 		slog.String("trace_id", telemetry.TraceID(ctx)),
 	)
 
@@ -49,17 +48,17 @@ func (s *Server) UpsertCustomer(ctx context.Context, req *customerpb.UpsertCusto
 
 func (s *Server) GetCustomer(ctx context.Context, req *customerpb.GetCustomerRequest) (*customerpb.CustomerResponse, error) {
 	if req == nil {
-		return nil, status.Error(codes.InvalidArgument, "request is required")
+		return nil, statusError(ctx, codes.InvalidArgument, domain.CodeInvalidRequest)
 	}
 
 	customer, err := s.service.GetCustomer(ctx, req.GetIdn())
 	if err != nil {
-		return nil, mapError(err)
+		return nil, mapError(ctx, err)
 	}
 
 	s.logger.Info(
 		"get_customer",
-		slog.String("idn", req.GetIdn()),
+		slog.String("idn", telemetry.MaskIDN(req.GetIdn())),
 		slog.String("trace_id", telemetry.TraceID(ctx)),
 	)
 
@@ -70,13 +69,25 @@ func (s *Server) GetCustomer(ctx context.Context, req *customerpb.GetCustomerReq
 	}, nil
 }
 
-func mapError(err error) error {
-	switch {
-	case errors.Is(err, service.ErrInvalidIDN):
-		return status.Error(codes.InvalidArgument, err.Error())
-	case errors.Is(err, service.ErrNotFound):
-		return status.Error(codes.NotFound, err.Error())
-	default:
-		return status.Error(codes.Internal, "internal error")
+func (s *Server) EraseCustomer(ctx context.Context, req *customerpb.EraseCustomerRequest) (*customerpb.EraseCustomerResponse, error) {
+	if req == nil {
+		return nil, statusError(ctx, codes.InvalidArgument, domain.CodeInvalidRequest)
 	}
+
+	erasure, err := s.service.EraseCustomer(ctx, req.GetIdn(), req.GetReason())
+	if err != nil {
+		return nil, mapError(ctx, err)
+	}
+
+	s.logger.Info(
+		"erase_customer",
+		slog.String("customer_id", erasure.CustomerID),
+		slog.String("trace_id", telemetry.TraceID(ctx)),
+	)
+
+	return &customerpb.EraseCustomerResponse{
+		Id:          erasure.CustomerID,
+		ErasedAt:    erasure.ErasedAt.UTC().Format(time.RFC3339),
+		ErasureHash: erasure.Hash,
+	}, nil
 }
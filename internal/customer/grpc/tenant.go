@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	customerpb "shipment-customer-service/api/proto"
+	domain "shipment-customer-service/internal/domain/customer"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/platform/tenant"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var errTenantlessPrincipal = errors.New("principal has no tenant")
+
+// crossTenantMethods may be called for every tenant at once. The shipment
+// service follows all customer changes to keep its cache fresh.
+var crossTenantMethods = map[string]bool{
+	customerpb.CustomerService_WatchCustomers_FullMethodName: true,
+}
+
+// TenantScope puts the tenant of every call into its context. Users act for
+// the tenant of their token; a token without one is rejected unless it carries
+// auth.RoleService. Such service accounts, and every caller while
+// authentication is disabled, name the tenant they act for in the x-tenant-id
+// metadata; it defaults to tenant.Default.
+type TenantScope struct {
+	logger *slog.Logger
+}
+
+func NewTenantScope(logger *slog.Logger) *TenantScope {
+	return &TenantScope{logger: logger}
+}
+
+func (s *TenantScope) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := s.scope(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (s *TenantScope) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := s.scope(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func (s *TenantScope) scope(ctx context.Context, method string) (context.Context, error) {
+	principal, authenticated := auth.FromContext(ctx)
+	requested := tenant.FromIncomingContext(ctx)
+
+	id, err := tenant.Resolve(principal.TenantID, requested, crossTenantMethods[method])
+	if authenticated && principal.TenantID == "" && !principal.HasRole(auth.RoleService) {
+		err = errTenantlessPrincipal
+	}
+	if err != nil {
+		s.logger.Warn(
+			"tenant_rejected",
+			slog.String("method", method),
+			slog.String("tenant_id", requested),
+			slog.String("reason", err.Error()),
+			slog.String("principal", principal.ID),
+			slog.String("trace_id", telemetry.TraceID(ctx)),
+		)
+		return nil, statusError(ctx, codes.PermissionDenied, domain.CodeInvalidTenant)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("tenant.id", id))
+	return tenant.NewContext(ctx, id), nil
+}
@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	customerpb "shipment-customer-service/api/proto"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/tenant"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenantScopeUnary(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		principal  *auth.Principal
+		requested  string
+		want       codes.Code
+		wantTenant string
+	}{
+		{name: "unauthenticated default", method: customerpb.CustomerService_UpsertCustomer_FullMethodName, want: codes.OK, wantTenant: tenant.Default},
+		{name: "service picks tenant", method: customerpb.CustomerService_UpsertCustomer_FullMethodName, principal: &auth.Principal{ID: "user:shipment-service", Roles: []auth.Role{auth.RoleService}}, requested: "brand-b", want: codes.OK, wantTenant: "brand-b"},
+		{name: "service watches all tenants", method: customerpb.CustomerService_WatchCustomers_FullMethodName, principal: &auth.Principal{ID: "user:shipment-service", Roles: []auth.Role{auth.RoleService}}, requested: tenant.All, want: codes.OK, wantTenant: tenant.All},
+		{name: "user without tenant", method: customerpb.CustomerService_GetCustomer_FullMethodName, principal: &auth.Principal{ID: "user:u-1"}, requested: "brand-b", want: codes.PermissionDenied},
+		{name: "user without tenant watches all tenants", method: customerpb.CustomerService_WatchCustomers_FullMethodName, principal: &auth.Principal{ID: "user:u-1", Roles: []auth.Role{auth.RoleOperator}}, requested: tenant.All, want: codes.PermissionDenied},
+		{name: "user tenant", method: customerpb.CustomerService_GetCustomer_FullMethodName, principal: &auth.Principal{ID: "user:u-1", TenantID: "brand-a"}, want: codes.OK, wantTenant: "brand-a"},
+		{name: "user asks for another tenant", method: customerpb.CustomerService_GetCustomer_FullMethodName, principal: &auth.Principal{ID: "user:u-1", TenantID: "brand-a"}, requested: "brand-b", want: codes.PermissionDenied},
+		{name: "all tenants for upsert", method: customerpb.CustomerService_UpsertCustomer_FullMethodName, requested: tenant.All, want: codes.PermissionDenied},
+		{name: "malformed tenant", method: customerpb.CustomerService_UpsertCustomer_FullMethodName, requested: "../brand", want: codes.PermissionDenied},
+	}
+
+	interceptor := NewTenantScope(slog.New(slog.NewTextHandler(io.Discard, nil))).Unary()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.NewContext(ctx, *tt.principal)
+			}
+			if tt.requested != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(tenant.MetadataKey, tt.requested))
+			}
+
+			var got string
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				got, _ = tenant.FromContext(ctx)
+				return nil, nil
+			})
+
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("interceptor code = %v, want %v", code, tt.want)
+			}
+			if got != tt.wantTenant {
+				t.Fatalf("interceptor tenant = %q, want %q", got, tt.wantTenant)
+			}
+		})
+	}
+}
+
+// TestTenantlessTokenIsRejected runs tokens through authentication and tenant
+// scoping: only service tokens may leave out the tenant claim.
+func TestTenantlessTokenIsRejected(t *testing.T) {
+	verifier, sign := testTokens(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	authenticator, scope := NewAuthenticator(verifier, logger), NewTenantScope(logger)
+
+	tests := []struct {
+		name   string
+		claims map[string]any
+		want   codes.Code
+	}{
+		{name: "no tenant claim", claims: map[string]any{"scope": "customers:read"}, want: codes.PermissionDenied},
+		{name: "no tenant claim with role", claims: map[string]any{"roles": []string{"operator"}}, want: codes.PermissionDenied},
+		{name: "service", claims: map[string]any{"scope": "customers:read", "roles": []string{"service"}}, want: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := sign(tt.claims)
+
+			t.Run("get", func(t *testing.T) {
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token, tenant.MetadataKey, "brand-b"))
+				info := &grpc.UnaryServerInfo{FullMethod: customerpb.CustomerService_GetCustomer_FullMethodName}
+				_, err := authenticator.Unary()(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+					return scope.Unary()(ctx, req, info, func(context.Context, any) (any, error) { return nil, nil })
+				})
+				if code := status.Code(err); code != tt.want {
+					t.Fatalf("GetCustomer code = %v, want %v", code, tt.want)
+				}
+			})
+
+			t.Run("watch", func(t *testing.T) {
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token, tenant.MetadataKey, tenant.All))
+				info := &grpc.StreamServerInfo{FullMethod: customerpb.CustomerService_WatchCustomers_FullMethodName, IsServerStream: true}
+				err := authenticator.Stream()(nil, &contextStream{ctx: ctx}, info, func(srv any, stream grpc.ServerStream) error {
+					return scope.Stream()(srv, stream, info, func(any, grpc.ServerStream) error { return nil })
+				})
+				if code := status.Code(err); code != tt.want {
+					t.Fatalf("WatchCustomers code = %v, want %v", code, tt.want)
+				}
+			})
+		})
+	}
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
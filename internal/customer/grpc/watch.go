@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"log/slog"
+	"time"
+
+	customerpb "shipment-customer-service/api/proto"
+	domain "shipment-customer-service/internal/domain/customer"
+	"shipment-customer-service/internal/platform/telemetry"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	watchBatchSize    = 100
+	watchPollInterval = time.Second
+)
+
+var changeTypes = map[string]customerpb.CustomerEventType{
+	domain.ChangeCreated: customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_CREATED,
+	domain.ChangeUpdated: customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_UPDATED,
+	domain.ChangeMerged:  customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_MERGED,
+	domain.ChangeErased:  customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_ERASED,
+}
+
+// WatchCustomers streams the customer change log from the requested cursor
+// and then follows it until the client goes away. Each event carries its
+// cursor so clients can resume after a disconnect without gaps.
+func (s *Server) WatchCustomers(req *customerpb.WatchCustomersRequest, stream grpc.ServerStreamingServer[customerpb.CustomerEvent]) error {
+	ctx := stream.Context()
+	if req == nil {
+		return statusError(ctx, codes.InvalidArgument, domain.CodeInvalidRequest)
+	}
+	if req.GetCursor() < 0 {
+		return statusError(ctx, codes.InvalidArgument, domain.CodeInvalidCursor)
+	}
+
+	cursor := req.GetCursor()
+	if req.GetFromLatest() {
+		latest, err := s.service.LatestChangeSeq(ctx)
+		if err != nil {
+			return mapError(ctx, err)
+		}
+		cursor = latest
+	}
+
+	s.logger.Info(
+		"watch_customers",
+		slog.Int64("cursor", cursor),
+		slog.String("trace_id", telemetry.TraceID(ctx)),
+	)
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		changes, err := s.service.ListChanges(ctx, cursor, watchBatchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return mapError(ctx, err)
+		}
+
+		for _, change := range changes {
+			if err := stream.Send(&customerpb.CustomerEvent{
+				Cursor:       change.Seq,
+				TenantId:     change.TenantID,
+				Type:         changeTypes[change.Type],
+				CustomerId:   change.CustomerID,
+				Idn:          change.IDN,
+				MergedIntoId: change.MergedIntoID,
+				OccurredAt:   change.OccurredAt.UTC().Format(time.RFC3339Nano),
+			}); err != nil {
+				return err
+			}
+			cursor = change.Seq
+		}
+		if len(changes) == watchBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
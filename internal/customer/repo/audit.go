@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"shipment-customer-service/internal/platform/audit"
+)
+
+const (
+	auditEntityCustomer  = "customer"
+	auditCustomerCreated = "customer.created"
+	auditCustomerErased  = "customer.erased"
+)
+
+// appendAudit records a change of a customer made in tx. IDNs in the fields
+// are masked; the audit log outlives erasure.
+func appendAudit(ctx context.Context, tx *sql.Tx, tenantID, action, customerID string, before, after audit.Fields) error {
+	_, err := audit.Append(ctx, tx, audit.Entry{
+		TenantID:   tenantID,
+		Action:     action,
+		EntityType: auditEntityCustomer,
+		EntityID:   customerID,
+		Changes:    audit.Diff(before, after),
+	})
+	return err
+}
@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/customer"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+// sealedIDN is an IDN encrypted with the customer keyring.
+type sealedIDN struct {
+	keyID      sql.NullString
+	ciphertext []byte
+}
+
+// appendChange adds an entry to the customer change log. It must be the last
+// statement of tx: the lock it takes is held until commit, so sequence numbers
+// are handed out in commit order and a reader that has seen an entry has seen
+// every entry before it. Readers following the log by seq therefore never skip
+// a late commit. idn is only stored with ERASED entries, whose customer no
+// longer has one.
+func appendChange(ctx context.Context, tx *sql.Tx, change domain.Change, idn sealedIDN) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('customer_changes'))`); err != nil {
+		return err
+	}
+	occurredAt := change.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO customer_changes (customer_id, tenant_id, change_type, merged_into_id, occurred_at, idn_ciphertext, idn_key_id)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7)
+	`, change.CustomerID, change.TenantID, change.Type, change.MergedIntoID, occurredAt, idn.ciphertext, idn.keyID)
+	return err
+}
+
+// ForgetErasedIDNs clears the IDN kept with ERASED entries that occurred
+// before the given time, across all tenants, and returns how many were
+// cleared.
+func (r *PostgresRepo) ForgetErasedIDNs(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "customer.repo.ForgetErasedIDNs")
+	defer span.End()
+
+	tx, err := tenant.BeginTx(tenant.NewContext(ctx, tenant.All), r.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE customer_changes
+		SET idn_ciphertext = NULL, idn_key_id = NULL
+		WHERE change_type = $1 AND idn_ciphertext IS NOT NULL AND occurred_at < $2
+	`, domain.ChangeErased, before)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
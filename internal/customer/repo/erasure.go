@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	domain "shipment-customer-service/internal/domain/customer"
+	"shipment-customer-service/internal/platform/audit"
+	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+// EraseCustomer pseudonymises the customer identified by idn and appends an
+// erasure record to the hash chain. The customer row and its shipments are
+// kept; only the personal data is replaced, so a later upsert with the same
+// IDN creates a fresh customer. The ERASED change carries the IDN, encrypted,
+// so that shipment-service can drop it from its pending shipments. The
+// erasure chain spans all tenants. It returns sql.ErrNoRows, having changed
+// nothing, when no customer of the tenant has that IDN.
+func (r *PostgresRepo) EraseCustomer(ctx context.Context, idn, reason string) (domain.Erasure, error) {
+	ctx, span := r.tracer.Start(ctx, "customer.repo.EraseCustomer")
+	defer span.End()
+	span.SetAttributes(attribute.String("customer.idn", telemetry.MaskIDN(idn)))
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return domain.Erasure{}, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return domain.Erasure{}, err
+	}
+	defer tx.Rollback()
+
+	// Serialise writers so that every record links to the true chain head.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('customer_erasures'))`); err != nil {
+		return domain.Erasure{}, err
+	}
+
+	erasure := domain.Erasure{Reason: reason}
+	row := tx.QueryRowContext(ctx, `
+		UPDATE customers
+		SET idn = NULL, idn_index = NULL, idn_ciphertext = NULL, idn_key_id = NULL, erased_at = now()
+		WHERE tenant_id = $1 AND idn_index = $2 AND erased_at IS NULL
+		RETURNING id::text, erased_at
+	`, tenantID, r.keyring.BlindIndex(idn))
+	if err := row.Scan(&erasure.CustomerID, &erasure.ErasedAt); err != nil {
+		return domain.Erasure{}, err
+	}
+
+	row = tx.QueryRowContext(ctx, `SELECT hash FROM customer_erasures ORDER BY seq DESC LIMIT 1`)
+	if err := row.Scan(&erasure.PrevHash); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return domain.Erasure{}, err
+	}
+	erasure.Hash = ErasureHash(erasure)
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO customer_erasures (customer_id, reason, erased_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5)
+	`, erasure.CustomerID, erasure.Reason, erasure.ErasedAt, erasure.PrevHash, erasure.Hash); err != nil {
+		return domain.Erasure{}, err
+	}
+
+	if err := appendAudit(ctx, tx, tenantID, auditCustomerErased, erasure.CustomerID,
+		audit.Fields{"idn": telemetry.MaskIDN(idn), "erased": false},
+		audit.Fields{"idn": nil, "erased": true, "reason": erasure.Reason},
+	); err != nil {
+		return domain.Erasure{}, err
+	}
+
+	keyID, ciphertext, err := r.keyring.Encrypt([]byte(idn))
+	if err != nil {
+		return domain.Erasure{}, err
+	}
+	sealed := sealedIDN{keyID: sql.NullString{String: keyID, Valid: true}, ciphertext: ciphertext}
+	if err := appendChange(ctx, tx, domain.Change{TenantID: tenantID, Type: domain.ChangeErased, CustomerID: erasure.CustomerID, OccurredAt: erasure.ErasedAt}, sealed); err != nil {
+		return domain.Erasure{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Erasure{}, err
+	}
+
+	return erasure, nil
+}
+
+// ErasureHash computes the chained hash of an erasure record. Changing any
+// stored record invalidates the hashes of all records after it.
+func ErasureHash(erasure domain.Erasure) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		erasure.PrevHash,
+		erasure.CustomerID,
+		erasure.Reason,
+		erasure.ErasedAt.UTC().Format(time.RFC3339Nano),
+	}, "\n")))
+	return hex.EncodeToString(sum[:])
+}
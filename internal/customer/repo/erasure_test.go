@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"testing"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/customer"
+)
+
+func TestErasureHash(t *testing.T) {
+	base := domain.Erasure{
+		CustomerID: "5f0c7a1e-8a43-4a53-9a43-3f1b1d0b7c11",
+		Reason:     "data subject request",
+		ErasedAt:   time.Date(2024, 1, 1, 12, 0, 0, 123456000, time.UTC),
+		PrevHash:   "0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c4b5a69788796a5b4c3d2e1f0",
+	}
+	want := ErasureHash(base)
+	if len(want) != 64 {
+		t.Fatalf("ErasureHash() = %q, want 64 hex characters", want)
+	}
+
+	tests := []struct {
+		name     string
+		change   func(e *domain.Erasure)
+		wantSame bool
+	}{
+		{name: "same record", change: func(e *domain.Erasure) {}, wantSame: true},
+		{name: "other time zone", change: func(e *domain.Erasure) { e.ErasedAt = e.ErasedAt.In(time.FixedZone("ALMT", 5*60*60)) }, wantSame: true},
+		{name: "stored hash ignored", change: func(e *domain.Erasure) { e.Hash = "anything" }, wantSame: true},
+		{name: "customer", change: func(e *domain.Erasure) { e.CustomerID = "6a1d8b2f-9b54-4b64-8b54-4a2c2e1c8d22" }},
+		{name: "reason", change: func(e *domain.Erasure) { e.Reason = "court order" }},
+		{name: "time", change: func(e *domain.Erasure) { e.ErasedAt = e.ErasedAt.Add(time.Microsecond) }},
+		{name: "previous hash", change: func(e *domain.Erasure) { e.PrevHash = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			erasure := base
+			tt.change(&erasure)
+			if got := ErasureHash(erasure); (got == want) != tt.wantSame {
+				t.Fatalf("ErasureHash() = %q, base %q, want same = %v", got, want, tt.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// metrics are business counters, recorded once the transaction they count
+// has committed.
+type metrics struct {
+	upserted metric.Int64Counter
+}
+
+// newMetrics cannot fail the repository: instruments that could not be
+// created are reported to the OpenTelemetry error handler and record nothing.
+func newMetrics() metrics {
+	upserted, err := otel.Meter("customer-db").Int64Counter(
+		"customers.upserted",
+		metric.WithDescription("Customer upserts, by result: created or existing"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return metrics{upserted: upserted}
+}
+
+func (m metrics) customerUpserted(ctx context.Context, inserted bool) {
+	result := "existing"
+	if inserted {
+		result = "created"
+	}
+	m.upserted.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
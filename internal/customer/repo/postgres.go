@@ -3,19 +3,29 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	domain "shipment-customer-service/internal/domain/customer"
+	"shipment-customer-service/internal/platform/audit"
+	"shipment-customer-service/internal/platform/keyring"
+	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/platform/tenant"
 )
 
+var ErrIDNMismatch = errors.New("decrypted idn does not match blind index")
+
 type PostgresRepo struct {
-	db     *sql.DB
-	tracer trace.Tracer
+	db      *sql.DB
+	tracer  trace.Tracer
+	keyring *keyring.Keyring
+	metrics metrics
 }
 
-func NewPostgresRepo(db *sql.DB, tracer trace.Tracer) *PostgresRepo {
-	return &PostgresRepo{db: db, tracer: tracer}
+func NewPostgresRepo(db *sql.DB, tracer trace.Tracer, keys *keyring.Keyring) *PostgresRepo {
+	return &PostgresRepo{db: db, tracer: tracer, keyring: keys, metrics: newMetrics()}
 }
 
 func (r *PostgresRepo) Ping(ctx context.Context) error {
@@ -25,18 +35,50 @@ func (r *PostgresRepo) Ping(ctx context.Context) error {
 func (r *PostgresRepo) UpsertCustomer(ctx context.Context, idn string) (domain.Customer, error) {
 	ctx, span := r.tracer.Start(ctx, "customer.repo.UpsertCustomer")
 	defer span.End()
+	span.SetAttributes(attribute.String("customer.idn", telemetry.MaskIDN(idn)))
 
-	row := r.db.QueryRowContext(ctx, `
-		INSERT INTO customers (id, idn)
-		VALUES ($1, $2)
-		ON CONFLICT (idn) DO UPDATE SET idn = EXCLUDED.idn
-		RETURNING id::text, idn, created_at
-	`, uuid.NewString(), idn)
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return domain.Customer{}, err
+	}
+	keyID, ciphertext, err := r.keyring.Encrypt([]byte(idn))
+	if err != nil {
+		return domain.Customer{}, err
+	}
 
-	var customer domain.Customer
-	if err := row.Scan(&customer.ID, &customer.IDN, &customer.CreatedAt); err != nil {
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return domain.Customer{}, err
+	}
+	defer tx.Rollback()
+
+	// xmax is zero only for freshly inserted rows, which is how a new customer
+	// is told apart from an existing one for the change log.
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO customers (id, tenant_id, idn_index, idn_ciphertext, idn_key_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, idn_index) DO UPDATE SET idn_index = EXCLUDED.idn_index
+		RETURNING id::text, created_at, xmax = 0 AS inserted
+	`, uuid.NewString(), tenantID, r.keyring.BlindIndex(idn), ciphertext, keyID)
+
+	customer := domain.Customer{IDN: idn}
+	var inserted bool
+	if err := row.Scan(&customer.ID, &customer.CreatedAt, &inserted); err != nil {
+		return domain.Customer{}, err
+	}
+	// Upserting a known customer changes nothing worth auditing or logging.
+	if inserted {
+		if err := appendAudit(ctx, tx, tenantID, auditCustomerCreated, customer.ID, nil, audit.Fields{"idn": telemetry.MaskIDN(idn)}); err != nil {
+			return domain.Customer{}, err
+		}
+		if err := appendChange(ctx, tx, domain.Change{TenantID: tenantID, Type: domain.ChangeCreated, CustomerID: customer.ID, OccurredAt: customer.CreatedAt}, sealedIDN{}); err != nil {
+			return domain.Customer{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
 		return domain.Customer{}, err
 	}
+	r.metrics.customerUpserted(ctx, inserted)
 
 	return customer, nil
 }
@@ -44,17 +86,221 @@ func (r *PostgresRepo) UpsertCustomer(ctx context.Context, idn string) (domain.C
 func (r *PostgresRepo) GetCustomerByIDN(ctx context.Context, idn string) (domain.Customer, error) {
 	ctx, span := r.tracer.Start(ctx, "customer.repo.GetCustomerByIDN")
 	defer span.End()
+	span.SetAttributes(attribute.String("customer.idn", telemetry.MaskIDN(idn)))
 
-	row := r.db.QueryRowContext(ctx, `
-		SELECT id::text, idn, created_at
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return domain.Customer{}, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return domain.Customer{}, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		SELECT id::text, idn_ciphertext, idn_key_id, created_at
 		FROM customers
-		WHERE idn = $1
-	`, idn)
+		WHERE tenant_id = $1 AND idn_index = $2
+	`, tenantID, r.keyring.BlindIndex(idn))
 
 	var customer domain.Customer
-	if err := row.Scan(&customer.ID, &customer.IDN, &customer.CreatedAt); err != nil {
+	var ciphertext []byte
+	var keyID string
+	if err := row.Scan(&customer.ID, &ciphertext, &keyID, &customer.CreatedAt); err != nil {
+		return domain.Customer{}, err
+	}
+
+	plaintext, err := r.keyring.Decrypt(keyID, ciphertext)
+	if err != nil {
 		return domain.Customer{}, err
 	}
+	if string(plaintext) != idn {
+		return domain.Customer{}, ErrIDNMismatch
+	}
+	customer.IDN = idn
 
 	return customer, nil
 }
+
+// ReencryptBatch moves up to limit customers onto the primary key: legacy rows
+// that still hold a plaintext IDN and rows encrypted under a retired key. It
+// returns the number of rows rewritten. It spans tenants. Every rewritten
+// customer is logged as UPDATED. A legacy row whose IDN was meanwhile upserted
+// again, and so has an indexed twin in its tenant, is merged into the twin:
+// its IDN is cleared and it is logged as MERGED.
+func (r *PostgresRepo) ReencryptBatch(ctx context.Context, limit int) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "customer.repo.ReencryptBatch")
+	defer span.End()
+
+	tx, err := tenant.BeginTx(tenant.NewContext(ctx, tenant.All), r.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id::text, tenant_id, idn, idn_ciphertext, idn_key_id
+		FROM customers
+		WHERE erased_at IS NULL AND merged_into_id IS NULL
+			AND (idn IS NOT NULL OR idn_key_id IS DISTINCT FROM $1)
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, r.keyring.Primary(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	type pending struct {
+		id         string
+		tenantID   string
+		plaintext  sql.NullString
+		ciphertext []byte
+		keyID      sql.NullString
+	}
+	var batch []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.tenantID, &p.plaintext, &p.ciphertext, &p.keyID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, p)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var changes []domain.Change
+	for _, p := range batch {
+		idn := p.plaintext.String
+		if !p.plaintext.Valid {
+			decrypted, err := r.keyring.Decrypt(p.keyID.String, p.ciphertext)
+			if err != nil {
+				return 0, err
+			}
+			idn = string(decrypted)
+		}
+		index := r.keyring.BlindIndex(idn)
+
+		var twinID string
+		err := tx.QueryRowContext(ctx, `
+			SELECT id::text FROM customers WHERE tenant_id = $1 AND idn_index = $2 AND id <> $3
+		`, p.tenantID, index, p.id).Scan(&twinID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+		if twinID != "" {
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE customers
+				SET idn = NULL, idn_index = NULL, idn_ciphertext = NULL, idn_key_id = NULL, merged_into_id = $2
+				WHERE id = $1
+			`, p.id, twinID); err != nil {
+				return 0, err
+			}
+			changes = append(changes, domain.Change{TenantID: p.tenantID, Type: domain.ChangeMerged, CustomerID: p.id, MergedIntoID: twinID})
+			continue
+		}
+
+		keyID, ciphertext, err := r.keyring.Encrypt([]byte(idn))
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE customers
+			SET idn = NULL, idn_index = $2, idn_ciphertext = $3, idn_key_id = $4
+			WHERE id = $1
+		`, p.id, index, ciphertext, keyID); err != nil {
+			return 0, err
+		}
+		changes = append(changes, domain.Change{TenantID: p.tenantID, Type: domain.ChangeUpdated, CustomerID: p.id})
+	}
+
+	for _, change := range changes {
+		if err := appendChange(ctx, tx, change, sealedIDN{}); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("customer.reencrypted", len(batch)))
+	return len(batch), nil
+}
+
+// ListChanges returns up to limit change-log entries after seq, oldest first.
+// A context scoped to tenant.All sees the changes of every tenant.
+func (r *PostgresRepo) ListChanges(ctx context.Context, seq int64, limit int) ([]domain.Change, error) {
+	ctx, span := r.tracer.Start(ctx, "customer.repo.ListChanges")
+	defer span.End()
+
+	scope, err := tenant.Scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT ch.seq, ch.tenant_id, ch.change_type, ch.customer_id::text, COALESCE(ch.merged_into_id::text, ''), ch.occurred_at,
+			COALESCE(ch.idn_ciphertext, c.idn_ciphertext), COALESCE(ch.idn_key_id, c.idn_key_id)
+		FROM customer_changes ch
+		JOIN customers c ON c.id = ch.customer_id
+		WHERE ch.seq > $1 AND ($3 = '*' OR ch.tenant_id = $3)
+		ORDER BY ch.seq
+		LIMIT $2
+	`, seq, limit, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []domain.Change
+	for rows.Next() {
+		var change domain.Change
+		var ciphertext []byte
+		var keyID sql.NullString
+		if err := rows.Scan(&change.Seq, &change.TenantID, &change.Type, &change.CustomerID, &change.MergedIntoID, &change.OccurredAt, &ciphertext, &keyID); err != nil {
+			return nil, err
+		}
+		if ciphertext != nil && keyID.Valid {
+			plaintext, err := r.keyring.Decrypt(keyID.String, ciphertext)
+			if err != nil {
+				return nil, err
+			}
+			change.IDN = string(plaintext)
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+func (r *PostgresRepo) LatestChangeSeq(ctx context.Context) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "customer.repo.LatestChangeSeq")
+	defer span.End()
+
+	scope, err := tenant.Scope(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var seq int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(max(seq), 0) FROM customer_changes WHERE $1 = '*' OR tenant_id = $1
+	`, scope).Scan(&seq)
+	return seq, err
+}
@@ -5,23 +5,36 @@ import (
 	"database/sql"
 	"errors"
 	"regexp"
+	"strings"
+	"time"
 
-	"shipment-customer-service/internal/customer/repo"
 	domain "shipment-customer-service/internal/domain/customer"
 )
 
 var idnPattern = regexp.MustCompile(`^\d{12}$`)
 
+const defaultErasureReason = "data subject request"
+
 var (
 	ErrInvalidIDN = errors.New("invalid idn")
 	ErrNotFound   = errors.New("customer not found")
 )
 
+type CustomerRepository interface {
+	UpsertCustomer(ctx context.Context, idn string) (domain.Customer, error)
+	GetCustomerByIDN(ctx context.Context, idn string) (domain.Customer, error)
+	EraseCustomer(ctx context.Context, idn, reason string) (domain.Erasure, error)
+	ReencryptBatch(ctx context.Context, limit int) (int, error)
+	ListChanges(ctx context.Context, seq int64, limit int) ([]domain.Change, error)
+	LatestChangeSeq(ctx context.Context) (int64, error)
+	ForgetErasedIDNs(ctx context.Context, before time.Time) (int64, error)
+}
+
 type Service struct {
-	repo *repo.PostgresRepo
+	repo CustomerRepository
 }
 
-func New(repository *repo.PostgresRepo) *Service {
+func New(repository CustomerRepository) *Service {
 	return &Service{repo: repository}
 }
 
@@ -48,3 +61,51 @@ func (s *Service) GetCustomer(ctx context.Context, idn string) (domain.Customer,
 
 	return customer, nil
 }
+
+func (s *Service) EraseCustomer(ctx context.Context, idn, reason string) (domain.Erasure, error) {
+	if !idnPattern.MatchString(idn) {
+		return domain.Erasure{}, ErrInvalidIDN
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = defaultErasureReason
+	}
+
+	erasure, err := s.repo.EraseCustomer(ctx, idn, reason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Erasure{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.Erasure{}, err
+	}
+
+	return erasure, nil
+}
+
+// ReencryptIDNs rewrites customers onto the primary encryption key in batches
+// until none are left, returning the total number of rows rewritten.
+func (s *Service) ReencryptIDNs(ctx context.Context, batchSize int) (int, error) {
+	total := 0
+	for {
+		n, err := s.repo.ReencryptBatch(ctx, batchSize)
+		total += n
+		if err != nil || n == 0 {
+			return total, err
+		}
+	}
+}
+
+func (s *Service) ListChanges(ctx context.Context, seq int64, limit int) ([]domain.Change, error) {
+	return s.repo.ListChanges(ctx, seq, limit)
+}
+
+func (s *Service) LatestChangeSeq(ctx context.Context) (int64, error) {
+	return s.repo.LatestChangeSeq(ctx)
+}
+
+// ForgetErasedIDNs clears the IDNs kept with erasures older than retention.
+// Consumers of the change stream must have caught up within that time.
+func (s *Service) ForgetErasedIDNs(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.ForgetErasedIDNs(ctx, time.Now().Add(-retention))
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/customer"
+)
+
+type mockRepo struct {
+	upsertFn func(ctx context.Context, idn string) (domain.Customer, error)
+	getFn    func(ctx context.Context, idn string) (domain.Customer, error)
+	eraseFn  func(ctx context.Context, idn, reason string) (domain.Erasure, error)
+}
+
+func (m *mockRepo) UpsertCustomer(ctx context.Context, idn string) (domain.Customer, error) {
+	if m.upsertFn == nil {
+		return domain.Customer{}, nil
+	}
+	return m.upsertFn(ctx, idn)
+}
+
+func (m *mockRepo) GetCustomerByIDN(ctx context.Context, idn string) (domain.Customer, error) {
+	if m.getFn == nil {
+		return domain.Customer{}, nil
+	}
+	return m.getFn(ctx, idn)
+}
+
+func (m *mockRepo) EraseCustomer(ctx context.Context, idn, reason string) (domain.Erasure, error) {
+	if m.eraseFn == nil {
+		return domain.Erasure{}, nil
+	}
+	return m.eraseFn(ctx, idn, reason)
+}
+
+func (m *mockRepo) ReencryptBatch(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+
+func (m *mockRepo) ListChanges(ctx context.Context, seq int64, limit int) ([]domain.Change, error) {
+	return nil, nil
+}
+
+func (m *mockRepo) LatestChangeSeq(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockRepo) ForgetErasedIDNs(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestEraseCustomer(t *testing.T) {
+	erasedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	stored := domain.Erasure{CustomerID: "customer-1", ErasedAt: erasedAt, PrevHash: "prev", Hash: "hash"}
+	dbDown := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		idn        string
+		reason     string
+		repoErr    error
+		wantCalled bool
+		wantReason string
+		err        error
+	}{
+		{name: "erased", idn: "990101123456", reason: "customer request", wantCalled: true, wantReason: "customer request"},
+		{name: "reason trimmed", idn: "990101123456", reason: "  court order  ", wantCalled: true, wantReason: "court order"},
+		{name: "default reason", idn: "990101123456", reason: "   ", wantCalled: true, wantReason: defaultErasureReason},
+		{name: "invalid idn", idn: "99010112345", err: ErrInvalidIDN},
+		{name: "idn with letters", idn: "99010112345a", err: ErrInvalidIDN},
+		{name: "not found", idn: "990101123456", repoErr: sql.ErrNoRows, wantCalled: true, wantReason: defaultErasureReason, err: ErrNotFound},
+		{name: "repo error", idn: "990101123456", repoErr: dbDown, wantCalled: true, wantReason: defaultErasureReason, err: dbDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				called    bool
+				gotIDN    string
+				gotReason string
+			)
+			svc := New(&mockRepo{eraseFn: func(ctx context.Context, idn, reason string) (domain.Erasure, error) {
+				called, gotIDN, gotReason = true, idn, reason
+				if tt.repoErr != nil {
+					return domain.Erasure{}, tt.repoErr
+				}
+				erasure := stored
+				erasure.Reason = reason
+				return erasure, nil
+			}})
+
+			erasure, err := svc.EraseCustomer(context.Background(), tt.idn, tt.reason)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("EraseCustomer() error = %v, want %v", err, tt.err)
+			}
+			if called != tt.wantCalled {
+				t.Fatalf("EraseCustomer() reached the repo = %v, want %v", called, tt.wantCalled)
+			}
+			if !called {
+				return
+			}
+			if gotIDN != tt.idn || gotReason != tt.wantReason {
+				t.Fatalf("repo called with (%q, %q), want (%q, %q)", gotIDN, gotReason, tt.idn, tt.wantReason)
+			}
+			if err != nil {
+				if erasure != (domain.Erasure{}) {
+					t.Fatalf("EraseCustomer() erasure = %+v on error, want zero", erasure)
+				}
+				return
+			}
+			if erasure.CustomerID != stored.CustomerID || erasure.Hash != stored.Hash || erasure.Reason != tt.wantReason {
+				t.Fatalf("EraseCustomer() erasure = %+v", erasure)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package apikey
+
+import "errors"
+
+var (
+	ErrInvalidName    = errors.New("api key name must not be empty")
+	ErrInvalidScope   = errors.New("invalid api key scope")
+	ErrInvalidID      = errors.New("invalid api key id")
+	ErrKeyNotFound    = errors.New("api key not found")
+	ErrMissingKey     = errors.New("api key required")
+	ErrInvalidKey     = errors.New("invalid or revoked api key")
+	ErrBootstrapShort = errors.New("bootstrap api key must be at least 32 characters")
+)
@@ -0,0 +1,42 @@
+package apikey
+
+import (
+	"time"
+
+	"shipment-customer-service/internal/platform/auth"
+)
+
+// Key is an issued API key. Only a hash of the secret is stored; Prefix is
+// kept so that keys can be told apart in listings.
+type Key struct {
+	ID         string
+	TenantID   string
+	Name       string
+	Prefix     string
+	Scopes     []auth.Scope
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+type IssueKeyInput struct {
+	Name   string
+	Scopes []auth.Scope
+}
+
+type IssueKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type KeyResponse struct {
+	ID         string   `json:"id"`
+	TenantID   string   `json:"tenant_id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	Key        string   `json:"key,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	RevokedAt  string   `json:"revoked_at,omitempty"`
+}
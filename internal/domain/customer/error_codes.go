@@ -0,0 +1,64 @@
+package customer
+
+import "shipment-customer-service/internal/platform/i18n"
+
+// ErrorDomain is the ErrorInfo domain of errors raised by the customer
+// service; the reason is one of the codes below.
+const ErrorDomain = "customer-service"
+
+// Error codes are part of the API contract: add new ones, never rename or
+// reuse existing ones.
+const (
+	CodeInvalidRequest   = "invalid_request"
+	CodeInvalidCursor    = "invalid_cursor"
+	CodeInvalidIDN       = "invalid_idn"
+	CodeCustomerNotFound = "customer_not_found"
+	CodeInternal         = "internal_error"
+	CodeUnauthenticated  = "unauthenticated"
+	CodeForbidden        = "forbidden"
+	CodeInvalidTenant    = "invalid_tenant"
+)
+
+// ErrorMessages holds the human-readable message of every error code.
+var ErrorMessages = i18n.Catalog{
+	CodeInvalidRequest: {
+		i18n.English: "Request is required",
+		i18n.Russian: "Запрос не передан",
+		i18n.Kazakh:  "Сұрау берілмеген",
+	},
+	CodeInvalidCursor: {
+		i18n.English: "Cursor must not be negative",
+		i18n.Russian: "Курсор не может быть отрицательным",
+		i18n.Kazakh:  "Курсор теріс болмауы керек",
+	},
+	CodeInvalidIDN: {
+		i18n.English: "Customer IDN must be 12 digits",
+		i18n.Russian: "ИИН клиента должен состоять из 12 цифр",
+		i18n.Kazakh:  "Клиенттің ЖСН-і 12 цифрдан тұруы керек",
+	},
+	CodeCustomerNotFound: {
+		i18n.English: "Customer not found",
+		i18n.Russian: "Клиент не найден",
+		i18n.Kazakh:  "Клиент табылмады",
+	},
+	CodeInternal: {
+		i18n.English: "Internal error",
+		i18n.Russian: "Внутренняя ошибка",
+		i18n.Kazakh:  "Ішкі қате",
+	},
+	CodeUnauthenticated: {
+		i18n.English: "A valid bearer token is required",
+		i18n.Russian: "Требуется действующий токен",
+		i18n.Kazakh:  "Жарамды токен қажет",
+	},
+	CodeForbidden: {
+		i18n.English: "The token does not grant access to this operation",
+		i18n.Russian: "Недостаточно прав для этой операции",
+		i18n.Kazakh:  "Бұл әрекетке рұқсат жеткіліксіз",
+	},
+	CodeInvalidTenant: {
+		i18n.English: "Tenant ID is invalid or not available to the caller",
+		i18n.Russian: "Идентификатор тенанта неверен или недоступен",
+		i18n.Kazakh:  "Тенант идентификаторы қате немесе қолжетімсіз",
+	},
+}
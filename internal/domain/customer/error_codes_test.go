@@ -0,0 +1,44 @@
+package customer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEveryErrorCodeIsTranslated(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "error_codes.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse error_codes.go: %v", err)
+	}
+
+	// Walk the declarations rather than the catalogue so a code that was
+	// never added to it is caught too.
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "Code") || i >= len(spec.Values) {
+				continue
+			}
+			literal, ok := spec.Values[i].(*ast.BasicLit)
+			if !ok {
+				continue
+			}
+			code, _ := strconv.Unquote(literal.Value)
+			if _, ok := ErrorMessages[code]; !ok {
+				t.Errorf("%s (%q) has no entry in ErrorMessages", name.Name, code)
+			}
+		}
+		return true
+	})
+
+	if missing := ErrorMessages.Missing(); len(missing) > 0 {
+		t.Fatalf("ErrorMessages lacks translations: %v", missing)
+	}
+}
@@ -2,8 +2,38 @@ package customer
 
 import "time"
 
+const (
+	ChangeCreated = "CREATED"
+	ChangeUpdated = "UPDATED"
+	ChangeMerged  = "MERGED"
+	ChangeErased  = "ERASED"
+)
+
 type Customer struct {
 	ID        string
 	IDN       string
 	CreatedAt time.Time
 }
+
+// Erasure is the tamper-evident record left behind when a customer's
+// personal data is pseudonymised. Hash chains over the previous record.
+type Erasure struct {
+	CustomerID string
+	Reason     string
+	ErasedAt   time.Time
+	PrevHash   string
+	Hash       string
+}
+
+// Change is an entry of the customer change log. IDN is empty when the
+// customer has since been erased, except on the ERASED entry itself, which
+// keeps the erased IDN for a while so that consumers can drop their copies.
+type Change struct {
+	Seq          int64
+	TenantID     string
+	Type         string
+	CustomerID   string
+	IDN          string
+	MergedIntoID string
+	OccurredAt   time.Time
+}
@@ -13,6 +13,13 @@ var (
 	ErrInvalidIDN        = errors.New("invalid idn")
 	ErrInvalidShipmentID = errors.New("invalid shipment id")
 	ErrNotFound          = errors.New("shipment not found")
+	ErrInvalidStatus     = errors.New("invalid status")
+	ErrInvalidTransition = errors.New("status transition not allowed")
+	ErrStatusConflict    = errors.New("shipment status changed concurrently")
+	ErrInvalidCustomerID = errors.New("invalid customer id")
+	ErrInvalidPageToken  = errors.New("invalid page token")
+	ErrForbidden         = errors.New("operation not permitted")
+	ErrQuotaExceeded     = errors.New("daily creation quota exceeded")
 )
 
 func IsValidIDN(value string) bool {
@@ -1,5 +1,26 @@
 package shipment
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix is joined with the problem code to form Problem.Type.
+const ProblemTypePrefix = "urn:shipment-service:problem:"
+
+// Problem is an RFC 7807 error response. Code is stable and is what clients
+// should branch on; Title and Detail are meant for humans and may change.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	TraceID  string       `json:"trace_id,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError points at one invalid request field with a JSON pointer.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"code"`
+	Detail  string `json:"detail"`
 }
@@ -0,0 +1,43 @@
+package shipment
+
+import "time"
+
+const (
+	EventShipmentCreated = "ShipmentCreated"
+	EventStatusChanged   = "StatusChanged"
+	EventCancelled       = "Cancelled"
+)
+
+// Event is a shipment domain event as stored in the outbox and handed to
+// publishers. Seq is the outbox position; ID is stable across redeliveries
+// and is what consumers should deduplicate on.
+type Event struct {
+	Seq        int64     `json:"seq"`
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	ShipmentID string    `json:"shipment_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       EventData `json:"data"`
+}
+
+type EventData struct {
+	TenantID       string  `json:"tenant_id"`
+	ShipmentID     string  `json:"shipment_id"`
+	CustomerID     string  `json:"customer_id"`
+	Route          string  `json:"route"`
+	Price          float64 `json:"price"`
+	Status         string  `json:"status"`
+	PreviousStatus string  `json:"previous_status,omitempty"`
+}
+
+func NewEventData(shipment Shipment, previousStatus string) EventData {
+	return EventData{
+		TenantID:       shipment.TenantID,
+		ShipmentID:     shipment.ID,
+		CustomerID:     shipment.CustomerID,
+		Route:          shipment.Route,
+		Price:          shipment.Price,
+		Status:         shipment.Status,
+		PreviousStatus: previousStatus,
+	}
+}
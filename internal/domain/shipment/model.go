@@ -4,11 +4,15 @@ import "time"
 
 type Shipment struct {
 	ID         string
+	TenantID   string
 	Route      string
 	Price      float64
 	Status     string
 	CustomerID string
-	CreatedAt  time.Time
+	// CustomerIDN is only set while the shipment waits for its customer to
+	// be resolved.
+	CustomerIDN string
+	CreatedAt   time.Time
 }
 
 type CreateShipmentInput struct {
@@ -17,6 +21,20 @@ type CreateShipmentInput struct {
 	CustomerIDN string
 }
 
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 100
+)
+
+// ListShipmentsFilter selects shipments newest first. Empty fields match
+// all; After is the ID of the last shipment of the previous page.
+type ListShipmentsFilter struct {
+	CustomerID string
+	Status     string
+	Limit      int
+	After      string
+}
+
 type CreateShipmentRequest struct {
 	Route    string                 `json:"route"`
 	Price    float64                `json:"price"`
@@ -33,6 +51,10 @@ type CreateShipmentResponse struct {
 	CustomerID string `json:"customerId"`
 }
 
+type TransitionShipmentRequest struct {
+	Status string `json:"status"`
+}
+
 type GetShipmentResponse struct {
 	ID         string  `json:"id"`
 	Route      string  `json:"route"`
@@ -41,3 +63,11 @@ type GetShipmentResponse struct {
 	CustomerID string  `json:"customerId"`
 	CreatedAt  string  `json:"created_at"`
 }
+
+type CreateShipmentsBatchRequest struct {
+	Shipments []CreateShipmentRequest `json:"shipments"`
+}
+
+type CreateShipmentsBatchResponse struct {
+	Shipments []CreateShipmentResponse `json:"shipments"`
+}
@@ -0,0 +1,230 @@
+package shipment
+
+import "shipment-customer-service/internal/platform/i18n"
+
+// Problem codes are part of the API contract: add new ones, never rename or
+// reuse existing ones.
+const (
+	CodeInvalidRequestBody         = "invalid_request_body"
+	CodeValidationFailed           = "validation_failed"
+	CodeInvalidRoute               = "invalid_route"
+	CodeInvalidPrice               = "invalid_price"
+	CodeInvalidIDN                 = "invalid_idn"
+	CodeEmptyBatch                 = "empty_batch"
+	CodeBatchTooLarge              = "batch_too_large"
+	CodeInvalidShipmentID          = "invalid_shipment_id"
+	CodeInvalidCustomerID          = "invalid_customer_id"
+	CodeInvalidStatus              = "invalid_status"
+	CodeInvalidLastEventID         = "invalid_last_event_id"
+	CodeShipmentNotFound           = "shipment_not_found"
+	CodeTransitionNotAllowed       = "transition_not_allowed"
+	CodeStatusConflict             = "status_conflict"
+	CodeCustomerRejected           = "customer_rejected"
+	CodeCustomerServiceUnavailable = "customer_service_unavailable"
+	CodeCustomerServiceTimeout     = "customer_service_timeout"
+	CodeCustomerServiceError       = "customer_service_error"
+	CodeInvalidWebhookURL          = "invalid_webhook_url"
+	CodeInvalidEventType           = "invalid_event_type"
+	CodeInvalidWebhookSecret       = "invalid_webhook_secret"
+	CodeInvalidID                  = "invalid_id"
+	CodeSubscriptionNotFound       = "subscription_not_found"
+	CodeDeliveryNotFound           = "delivery_not_found"
+	CodeUnauthenticated            = "unauthenticated"
+	CodeForbidden                  = "forbidden"
+	CodeInvalidAPIKeyName          = "invalid_api_key_name"
+	CodeInvalidScope               = "invalid_scope"
+	CodeAPIKeyNotFound             = "api_key_not_found"
+	CodeNotReady                   = "not_ready"
+	CodeStreamingUnsupported       = "streaming_unsupported"
+	CodeInvalidAuditFilter         = "invalid_audit_filter"
+	CodeRateLimited                = "rate_limited"
+	CodeQuotaExceeded              = "quota_exceeded"
+	CodeInternal                   = "internal_error"
+)
+
+// ProblemMessages holds the human-readable message of every problem code.
+var ProblemMessages = i18n.Catalog{
+	CodeInvalidRequestBody: {
+		i18n.English: "Request body is not valid JSON for this endpoint",
+		i18n.Russian: "Тело запроса не является корректным JSON для этого метода",
+		i18n.Kazakh:  "Сұрау денесі осы әдіс үшін жарамды JSON емес",
+	},
+	CodeValidationFailed: {
+		i18n.English: "Request validation failed",
+		i18n.Russian: "Запрос не прошёл проверку",
+		i18n.Kazakh:  "Сұрау тексеруден өтпеді",
+	},
+	CodeInvalidRoute: {
+		i18n.English: "Route must not be empty",
+		i18n.Russian: "Маршрут не должен быть пустым",
+		i18n.Kazakh:  "Бағыт бос болмауы керек",
+	},
+	CodeInvalidPrice: {
+		i18n.English: "Price must be greater than zero",
+		i18n.Russian: "Цена должна быть больше нуля",
+		i18n.Kazakh:  "Баға нөлден үлкен болуы керек",
+	},
+	CodeInvalidIDN: {
+		i18n.English: "Customer IDN must be 12 digits",
+		i18n.Russian: "ИИН клиента должен состоять из 12 цифр",
+		i18n.Kazakh:  "Клиенттің ЖСН-і 12 цифрдан тұруы керек",
+	},
+	CodeEmptyBatch: {
+		i18n.English: "Batch has no shipments",
+		i18n.Russian: "В пакете нет отправлений",
+		i18n.Kazakh:  "Топтамада жөнелтілімдер жоқ",
+	},
+	CodeBatchTooLarge: {
+		i18n.English: "Batch has more than 100 shipments",
+		i18n.Russian: "В пакете больше 100 отправлений",
+		i18n.Kazakh:  "Топтамада 100-ден артық жөнелтілім бар",
+	},
+	CodeInvalidShipmentID: {
+		i18n.English: "Shipment ID is invalid",
+		i18n.Russian: "Неверный идентификатор отправления",
+		i18n.Kazakh:  "Жөнелтілім идентификаторы қате",
+	},
+	CodeInvalidCustomerID: {
+		i18n.English: "Customer ID is invalid",
+		i18n.Russian: "Неверный идентификатор клиента",
+		i18n.Kazakh:  "Клиент идентификаторы қате",
+	},
+	CodeInvalidStatus: {
+		i18n.English: "Status is invalid",
+		i18n.Russian: "Неверный статус",
+		i18n.Kazakh:  "Мәртебе қате",
+	},
+	CodeInvalidLastEventID: {
+		i18n.English: "Last-Event-ID is invalid",
+		i18n.Russian: "Неверный Last-Event-ID",
+		i18n.Kazakh:  "Last-Event-ID қате",
+	},
+	CodeShipmentNotFound: {
+		i18n.English: "Shipment not found",
+		i18n.Russian: "Отправление не найдено",
+		i18n.Kazakh:  "Жөнелтілім табылмады",
+	},
+	CodeTransitionNotAllowed: {
+		i18n.English: "Status transition is not allowed",
+		i18n.Russian: "Такая смена статуса не разрешена",
+		i18n.Kazakh:  "Мәртебені бұлай өзгертуге болмайды",
+	},
+	CodeStatusConflict: {
+		i18n.English: "Shipment was changed concurrently",
+		i18n.Russian: "Отправление было изменено параллельно",
+		i18n.Kazakh:  "Жөнелтілім қатар өзгертілді",
+	},
+	CodeCustomerRejected: {
+		i18n.English: "Customer service rejected the customer",
+		i18n.Russian: "Сервис клиентов отклонил клиента",
+		i18n.Kazakh:  "Клиенттер сервисі клиентті қабылдамады",
+	},
+	CodeCustomerServiceUnavailable: {
+		i18n.English: "Customer service is unavailable",
+		i18n.Russian: "Сервис клиентов недоступен",
+		i18n.Kazakh:  "Клиенттер сервисі қолжетімсіз",
+	},
+	CodeCustomerServiceTimeout: {
+		i18n.English: "Customer service did not answer in time",
+		i18n.Russian: "Сервис клиентов не ответил вовремя",
+		i18n.Kazakh:  "Клиенттер сервисі уақытында жауап бермеді",
+	},
+	CodeCustomerServiceError: {
+		i18n.English: "Customer service failed",
+		i18n.Russian: "Ошибка сервиса клиентов",
+		i18n.Kazakh:  "Клиенттер сервисінің қатесі",
+	},
+	CodeInvalidWebhookURL: {
+		i18n.English: "Webhook URL is invalid",
+		i18n.Russian: "Неверный URL вебхука",
+		i18n.Kazakh:  "Вебхук URL мекенжайы қате",
+	},
+	CodeInvalidEventType: {
+		i18n.English: "Event type is invalid",
+		i18n.Russian: "Неверный тип события",
+		i18n.Kazakh:  "Оқиға түрі қате",
+	},
+	CodeInvalidWebhookSecret: {
+		i18n.English: "Webhook secret must be at least 16 characters",
+		i18n.Russian: "Секрет вебхука должен быть не короче 16 символов",
+		i18n.Kazakh:  "Вебхук құпиясы кемінде 16 таңбадан тұруы керек",
+	},
+	CodeInvalidID: {
+		i18n.English: "ID is invalid",
+		i18n.Russian: "Неверный идентификатор",
+		i18n.Kazakh:  "Идентификатор қате",
+	},
+	CodeSubscriptionNotFound: {
+		i18n.English: "Webhook subscription not found",
+		i18n.Russian: "Подписка на вебхук не найдена",
+		i18n.Kazakh:  "Вебхук жазылымы табылмады",
+	},
+	CodeDeliveryNotFound: {
+		i18n.English: "Webhook delivery not found",
+		i18n.Russian: "Доставка вебхука не найдена",
+		i18n.Kazakh:  "Вебхук жеткізілімі табылмады",
+	},
+	CodeUnauthenticated: {
+		i18n.English: "A valid API key or bearer token is required",
+		i18n.Russian: "Требуется действующий API-ключ или токен",
+		i18n.Kazakh:  "Жарамды API кілті немесе токен қажет",
+	},
+	CodeForbidden: {
+		i18n.English: "The credentials do not grant access to this operation",
+		i18n.Russian: "Недостаточно прав для этой операции",
+		i18n.Kazakh:  "Бұл әрекетке рұқсат жеткіліксіз",
+	},
+	CodeInvalidAPIKeyName: {
+		i18n.English: "API key name must not be empty",
+		i18n.Russian: "Имя API-ключа не должно быть пустым",
+		i18n.Kazakh:  "API кілтінің атауы бос болмауы керек",
+	},
+	CodeInvalidScope: {
+		i18n.English: "Scopes must be a non-empty list of shipments:read, shipments:write or admin",
+		i18n.Russian: "Укажите хотя бы одну область доступа: shipments:read, shipments:write или admin",
+		i18n.Kazakh:  "Кемінде бір қолжетімділік аясын көрсетіңіз: shipments:read, shipments:write немесе admin",
+	},
+	CodeAPIKeyNotFound: {
+		i18n.English: "API key not found",
+		i18n.Russian: "API-ключ не найден",
+		i18n.Kazakh:  "API кілті табылмады",
+	},
+	CodeNotReady: {
+		i18n.English: "Service is not ready",
+		i18n.Russian: "Сервис не готов",
+		i18n.Kazakh:  "Сервис дайын емес",
+	},
+	CodeStreamingUnsupported: {
+		i18n.English: "Streaming is not supported",
+		i18n.Russian: "Потоковая передача не поддерживается",
+		i18n.Kazakh:  "Ағынмен жіберуге қолдау көрсетілмейді",
+	},
+	CodeInvalidAuditFilter: {
+		i18n.English: "Audit log filter is invalid",
+		i18n.Russian: "Некорректный фильтр журнала аудита",
+		i18n.Kazakh:  "Аудит журналының сүзгісі жарамсыз",
+	},
+	CodeRateLimited: {
+		i18n.English: "Too many requests, retry after the time in Retry-After",
+		i18n.Russian: "Слишком много запросов, повторите после времени из Retry-After",
+		i18n.Kazakh:  "Сұраулар тым көп, Retry-After уақытынан кейін қайталаңыз",
+	},
+	CodeQuotaExceeded: {
+		i18n.English: "Daily shipment creation quota is used up",
+		i18n.Russian: "Дневная квота на создание отправлений исчерпана",
+		i18n.Kazakh:  "Жөнелтімдер құруға арналған күндік квота таусылды",
+	},
+	CodeInternal: {
+		i18n.English: "Internal error",
+		i18n.Russian: "Внутренняя ошибка",
+		i18n.Kazakh:  "Ішкі қате",
+	},
+}
+
+// ProblemTitle returns the message of a problem code in lang.
+func ProblemTitle(code string, lang i18n.Language) string {
+	if title := ProblemMessages.Message(code, lang); title != "" {
+		return title
+	}
+	return ProblemMessages.Message(CodeInternal, lang)
+}
@@ -0,0 +1,55 @@
+package shipment
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEveryProblemCodeIsTranslated(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "problem_codes.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse problem_codes.go: %v", err)
+	}
+
+	// Walk the declarations rather than the catalogue so a code that was
+	// never added to it is caught too.
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "Code") || i >= len(spec.Values) {
+				continue
+			}
+			literal, ok := spec.Values[i].(*ast.BasicLit)
+			if !ok {
+				continue
+			}
+			code, _ := strconv.Unquote(literal.Value)
+			if _, ok := ProblemMessages[code]; !ok {
+				t.Errorf("%s (%q) has no entry in ProblemMessages", name.Name, code)
+			}
+		}
+		return true
+	})
+
+	if missing := ProblemMessages.Missing(); len(missing) > 0 {
+		t.Fatalf("ProblemMessages lacks translations: %v", missing)
+	}
+}
+
+func TestProblemDetailsAreTranslated(t *testing.T) {
+	for code := range ProblemDetails {
+		if _, ok := ProblemMessages[code]; !ok {
+			t.Errorf("ProblemDetails has %q, which is not a problem code", code)
+		}
+	}
+	if missing := ProblemDetails.Missing(); len(missing) > 0 {
+		t.Fatalf("ProblemDetails lacks translations: %v", missing)
+	}
+}
@@ -0,0 +1,133 @@
+package shipment
+
+import "shipment-customer-service/internal/platform/i18n"
+
+// ProblemDetails explains problems that are not about a request field: what
+// went wrong and what the client can do about it. Validation problems build
+// their detail from the field errors instead.
+var ProblemDetails = i18n.Catalog{
+	CodeInvalidRequestBody: {
+		i18n.English: "The body could not be decoded; check that it is a single JSON object with the documented fields and no unknown ones",
+		i18n.Russian: "Тело не удалось разобрать: это должен быть один JSON-объект с описанными в документации полями, без лишних",
+		i18n.Kazakh:  "Денені талдау мүмкін болмады: бұл құжаттамада сипатталған өрістері бар, артығы жоқ бір JSON нысаны болуы керек",
+	},
+	CodeInvalidShipmentID: {
+		i18n.English: "Shipment IDs are UUIDs as returned when the shipment was created",
+		i18n.Russian: "Идентификатор отправления — UUID, который вернулся при его создании",
+		i18n.Kazakh:  "Жөнелтілім идентификаторы — ол құрылған кезде қайтарылған UUID",
+	},
+	CodeInvalidCustomerID: {
+		i18n.English: "The customer_id filter must be a customer UUID",
+		i18n.Russian: "Фильтр customer_id должен быть UUID клиента",
+		i18n.Kazakh:  "customer_id сүзгісі клиенттің UUID-і болуы керек",
+	},
+	CodeInvalidLastEventID: {
+		i18n.English: "Last-Event-ID must be the id of an event received from this stream",
+		i18n.Russian: "Last-Event-ID должен быть id события, полученного из этого потока",
+		i18n.Kazakh:  "Last-Event-ID осы ағыннан алынған оқиғаның id-і болуы керек",
+	},
+	CodeShipmentNotFound: {
+		i18n.English: "No shipment with this ID exists that the caller may see",
+		i18n.Russian: "Отправления с таким идентификатором нет или оно недоступно вызывающему",
+		i18n.Kazakh:  "Мұндай идентификаторы бар жөнелтілім жоқ немесе ол шақырушыға қолжетімсіз",
+	},
+	CodeTransitionNotAllowed: {
+		i18n.English: "The shipment's current status cannot change to the requested one",
+		i18n.Russian: "Из текущего статуса отправления нельзя перейти в запрошенный",
+		i18n.Kazakh:  "Жөнелтілімнің ағымдағы мәртебесінен сұралған мәртебеге өтуге болмайды",
+	},
+	CodeStatusConflict: {
+		i18n.English: "The status changed while the request was processed; read the shipment again and retry",
+		i18n.Russian: "Статус изменился во время обработки запроса; перечитайте отправление и повторите",
+		i18n.Kazakh:  "Сұрау өңделіп жатқанда мәртебе өзгерді; жөнелтілімді қайта оқып, қайталаңыз",
+	},
+	CodeCustomerRejected: {
+		i18n.English: "The customer service did not accept the customer IDN; check it and retry",
+		i18n.Russian: "Сервис клиентов не принял ИИН клиента; проверьте его и повторите",
+		i18n.Kazakh:  "Клиенттер сервисі клиенттің ЖСН-ін қабылдамады; оны тексеріп, қайталаңыз",
+	},
+	CodeCustomerServiceUnavailable: {
+		i18n.English: "The shipment was not created because customers cannot be resolved right now; retry later",
+		i18n.Russian: "Отправление не создано: клиентов сейчас нельзя проверить; повторите позже",
+		i18n.Kazakh:  "Жөнелтілім құрылмады: клиенттерді қазір тексеру мүмкін емес; кейінірек қайталаңыз",
+	},
+	CodeCustomerServiceTimeout: {
+		i18n.English: "The shipment was not created because resolving the customer took too long; retry later",
+		i18n.Russian: "Отправление не создано: проверка клиента заняла слишком много времени; повторите позже",
+		i18n.Kazakh:  "Жөнелтілім құрылмады: клиентті тексеру тым ұзаққа созылды; кейінірек қайталаңыз",
+	},
+	CodeCustomerServiceError: {
+		i18n.English: "The shipment was not created because the customer service failed; retry later or quote the trace ID to support",
+		i18n.Russian: "Отправление не создано из-за ошибки сервиса клиентов; повторите позже или сообщите trace ID в поддержку",
+		i18n.Kazakh:  "Клиенттер сервисінің қатесінен жөнелтілім құрылмады; кейінірек қайталаңыз немесе қолдау қызметіне trace ID хабарлаңыз",
+	},
+	CodeInvalidID: {
+		i18n.English: "IDs in the path are UUIDs as returned by the API",
+		i18n.Russian: "Идентификаторы в пути — UUID, которые возвращает API",
+		i18n.Kazakh:  "Жолдағы идентификаторлар — API қайтаратын UUID",
+	},
+	CodeSubscriptionNotFound: {
+		i18n.English: "No webhook subscription with this ID exists in the tenant",
+		i18n.Russian: "Подписки на вебхук с таким идентификатором в тенанте нет",
+		i18n.Kazakh:  "Тенантта мұндай идентификаторы бар вебхук жазылымы жоқ",
+	},
+	CodeDeliveryNotFound: {
+		i18n.English: "No delivery with this ID exists for the webhook subscription",
+		i18n.Russian: "У подписки на вебхук нет доставки с таким идентификатором",
+		i18n.Kazakh:  "Вебхук жазылымында мұндай идентификаторы бар жеткізілім жоқ",
+	},
+	CodeUnauthenticated: {
+		i18n.English: "Send an API key in X-API-Key or an SSO token in Authorization: Bearer",
+		i18n.Russian: "Передайте API-ключ в X-API-Key или токен SSO в Authorization: Bearer",
+		i18n.Kazakh:  "API кілтін X-API-Key тақырыбында немесе SSO токенін Authorization: Bearer тақырыбында жіберіңіз",
+	},
+	CodeForbidden: {
+		i18n.English: "The key's scopes or the token's roles do not allow this operation on this resource",
+		i18n.Russian: "Области доступа ключа или роли токена не разрешают эту операцию с этим ресурсом",
+		i18n.Kazakh:  "Кілттің қолжетімділік аялары немесе токеннің рөлдері бұл ресурспен бұл әрекетке рұқсат бермейді",
+	},
+	CodeAPIKeyNotFound: {
+		i18n.English: "No API key with this ID exists in the tenant",
+		i18n.Russian: "API-ключа с таким идентификатором в тенанте нет",
+		i18n.Kazakh:  "Тенантта мұндай идентификаторы бар API кілті жоқ",
+	},
+	CodeNotReady: {
+		i18n.English: "A dependency of the service is down; retry later",
+		i18n.Russian: "Недоступна одна из зависимостей сервиса; повторите позже",
+		i18n.Kazakh:  "Сервистің тәуелділіктерінің бірі қолжетімсіз; кейінірек қайталаңыз",
+	},
+	CodeStreamingUnsupported: {
+		i18n.English: "The connection cannot stream responses; connect without a buffering proxy in between",
+		i18n.Russian: "Соединение не поддерживает потоковые ответы; подключайтесь без буферизующего прокси",
+		i18n.Kazakh:  "Қосылым ағынды жауаптарды қолдамайды; буферлейтін проксисіз қосылыңыз",
+	},
+	CodeInvalidAuditFilter: {
+		i18n.English: "Times must be RFC 3339 with from before to; after, hash and limit must be as returned by the previous page",
+		i18n.Russian: "Время — в формате RFC 3339, from раньше to; after, hash и limit — как в предыдущей странице",
+		i18n.Kazakh:  "Уақыт RFC 3339 пішімінде, from to-дан ерте болуы керек; after, hash және limit алдыңғы беттегідей болуы керек",
+	},
+	CodeRateLimited: {
+		i18n.English: "The request rate of this client is over its limit; the RateLimit headers describe the bucket",
+		i18n.Russian: "Частота запросов клиента превысила лимит; заголовки RateLimit описывают его bucket",
+		i18n.Kazakh:  "Клиент сұрауларының жиілігі шектен асты; RateLimit тақырыптары оның bucket-ін сипаттайды",
+	},
+	CodeQuotaExceeded: {
+		i18n.English: "Nothing was created; the quota is renewed at midnight UTC, see Retry-After",
+		i18n.Russian: "Ничего не создано; квота обновляется в полночь UTC, см. Retry-After",
+		i18n.Kazakh:  "Ештеңе құрылмады; квота UTC бойынша түн ортасында жаңарады, Retry-After қараңыз",
+	},
+	CodeInternal: {
+		i18n.English: "The request failed on the server; retry later or quote the trace ID to support",
+		i18n.Russian: "Запрос не выполнен из-за ошибки на сервере; повторите позже или сообщите trace ID в поддержку",
+		i18n.Kazakh:  "Сұрау сервердегі қатеге байланысты орындалмады; кейінірек қайталаңыз немесе қолдау қызметіне trace ID хабарлаңыз",
+	},
+}
+
+// ProblemDetail returns the detail of a problem code in lang, or its title
+// for codes without one.
+func ProblemDetail(code string, lang i18n.Language) string {
+	if detail := ProblemDetails.Message(code, lang); detail != "" {
+		return detail
+	}
+	return ProblemTitle(code, lang)
+}
@@ -0,0 +1,15 @@
+package shipment
+
+import "time"
+
+// QuotaDay is the UTC day whose creation quota a shipment created at now
+// counts against.
+func QuotaDay(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// QuotaResetIn is how long after now the next quota day starts.
+func QuotaResetIn(now time.Time) time.Duration {
+	return QuotaDay(now).AddDate(0, 0, 1).Sub(now)
+}
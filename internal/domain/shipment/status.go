@@ -0,0 +1,36 @@
+package shipment
+
+const (
+	// StatusPendingCustomer marks a shipment accepted while the customer
+	// service was unavailable; it becomes CREATED once the customer is
+	// resolved.
+	StatusPendingCustomer = "PENDING_CUSTOMER"
+	StatusCreated         = "CREATED"
+	StatusInTransit       = "IN_TRANSIT"
+	StatusDelivered       = "DELIVERED"
+	StatusCancelled       = "CANCELLED"
+)
+
+var transitions = map[string][]string{
+	StatusPendingCustomer: {StatusCancelled},
+	StatusCreated:         {StatusInTransit, StatusCancelled},
+	StatusInTransit:       {StatusDelivered, StatusCancelled},
+}
+
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusPendingCustomer, StatusCreated, StatusInTransit, StatusDelivered, StatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
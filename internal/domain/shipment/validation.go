@@ -0,0 +1,103 @@
+package shipment
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// MaxBatchSize caps the number of shipments created in one batch request.
+const MaxBatchSize = 100
+
+var (
+	ErrEmptyBatch    = errors.New("batch has no shipments")
+	ErrBatchTooLarge = errors.New("batch has more than " + strconv.Itoa(MaxBatchSize) + " shipments")
+)
+
+// ValidationError lists every invalid field of a request. Fields point into
+// the JSON request body; errors.Is matches the sentinel errors behind them.
+type ValidationError struct {
+	Fields []FieldError
+	causes []error
+}
+
+// Add records that the field at pointer is invalid because of err.
+func (e *ValidationError) Add(pointer, code string, err error) {
+	e.Fields = append(e.Fields, FieldError{Pointer: pointer, Code: code, Detail: err.Error()})
+	e.causes = append(e.causes, err)
+}
+
+// Merge adds the fields of err, if it is a ValidationError, below prefix.
+// Any other non-nil error is recorded at prefix itself.
+func (e *ValidationError) Merge(prefix string, err error) {
+	var other *ValidationError
+	if !errors.As(err, &other) {
+		if err != nil {
+			e.Add(prefix, CodeValidationFailed, err)
+		}
+		return
+	}
+	for i, field := range other.Fields {
+		field.Pointer = prefix + field.Pointer
+		e.Fields = append(e.Fields, field)
+		e.causes = append(e.causes, other.causes[i])
+	}
+}
+
+// Err returns e, or nil if no field was recorded.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		parts[i] = field.Pointer + ": " + field.Detail
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.causes
+}
+
+// Normalize trims the free-text fields of the input.
+func (in CreateShipmentInput) Normalize() CreateShipmentInput {
+	in.Route = strings.TrimSpace(in.Route)
+	in.CustomerIDN = strings.TrimSpace(in.CustomerIDN)
+	return in
+}
+
+// Validate reports every invalid field of a normalized input.
+func (in CreateShipmentInput) Validate() error {
+	var v ValidationError
+	if in.Route == "" {
+		v.Add("/route", CodeInvalidRoute, ErrInvalidRoute)
+	}
+	if in.Price <= 0 {
+		v.Add("/price", CodeInvalidPrice, ErrInvalidPrice)
+	}
+	if !IsValidIDN(in.CustomerIDN) {
+		v.Add("/customer/idn", CodeInvalidIDN, ErrInvalidIDN)
+	}
+	return v.Err()
+}
+
+// ValidateBatch validates normalized batch inputs together, pointing each
+// field error at its item in the batch request.
+func ValidateBatch(inputs []CreateShipmentInput) error {
+	var v ValidationError
+	switch {
+	case len(inputs) == 0:
+		v.Add("/shipments", CodeEmptyBatch, ErrEmptyBatch)
+	case len(inputs) > MaxBatchSize:
+		v.Add("/shipments", CodeBatchTooLarge, ErrBatchTooLarge)
+	}
+	for i, input := range inputs {
+		v.Merge("/shipments/"+strconv.Itoa(i), input.Validate())
+	}
+	return v.Err()
+}
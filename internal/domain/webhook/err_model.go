@@ -0,0 +1,12 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrInvalidURL           = errors.New("invalid webhook url")
+	ErrInvalidEventType     = errors.New("invalid event type")
+	ErrInvalidSecret        = errors.New("webhook secret must be at least 16 characters")
+	ErrInvalidID            = errors.New("invalid webhook id")
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+)
@@ -0,0 +1,300 @@
+// Package audit keeps a tamper-evident record of who changed what in both
+// services. Entries are appended in the transaction of the change they
+// describe and chained by hash per tenant, so editing or deleting a stored
+// entry breaks the chain from there on.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/telemetry"
+)
+
+// SystemActor is recorded for changes made without an authenticated caller:
+// background workers and internal calls while authentication is disabled.
+const SystemActor = "system"
+
+const (
+	DefaultListLimit   = 100
+	MaxListLimit       = 1000
+	DefaultVerifyLimit = 1000
+	MaxVerifyLimit     = 10000
+)
+
+var (
+	ErrNoTenant      = errors.New("audit entry needs a tenant")
+	ErrInvalidFilter = errors.New("invalid audit filter")
+)
+
+// Change holds the value of a field before and after a mutation. Before is
+// nil for created entities, After for deleted ones.
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type Entry struct {
+	Seq        int64
+	TenantID   string
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	Changes    map[string]Change
+	RequestID  string
+	TraceID    string
+	OccurredAt time.Time
+	PrevHash   string
+	Hash       string
+}
+
+// Fields is the audited state of an entity. Values are stored as JSON, so
+// times are best passed as formatted strings. Personal data is masked before
+// it gets here.
+type Fields map[string]any
+
+// Diff returns the fields whose values differ between before and after. A nil
+// before describes a creation, a nil after a deletion.
+func Diff(before, after Fields) map[string]Change {
+	changes := make(map[string]Change)
+	for key, value := range before {
+		if next, ok := after[key]; !ok || !reflect.DeepEqual(value, next) {
+			changes[key] = Change{Before: value, After: after[key]}
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changes[key] = Change{After: value}
+		}
+	}
+	return changes
+}
+
+// EntryResponse is an entry as returned by the query API.
+type EntryResponse struct {
+	Seq        int64             `json:"seq"`
+	Actor      string            `json:"actor"`
+	Action     string            `json:"action"`
+	EntityType string            `json:"entity_type"`
+	EntityID   string            `json:"entity_id"`
+	Changes    map[string]Change `json:"changes"`
+	RequestID  string            `json:"request_id"`
+	TraceID    string            `json:"trace_id"`
+	OccurredAt string            `json:"occurred_at"`
+	PrevHash   string            `json:"prev_hash"`
+	Hash       string            `json:"hash"`
+}
+
+// EntriesResponse is a page of entries as returned by the query API.
+type EntriesResponse struct {
+	Entries []EntryResponse `json:"entries"`
+	// NextAfter continues the listing; it is 0 on the last page.
+	NextAfter int64 `json:"next_after"`
+}
+
+// ChainResponse reports the verification of a part of a tenant's hash chain.
+// NextAfter and Hash, when set, are passed as after and hash to verify the
+// next part.
+type ChainResponse struct {
+	Entries   int    `json:"entries"`
+	Valid     bool   `json:"valid"`
+	BrokenAt  int64  `json:"broken_at,omitempty"`
+	NextAfter int64  `json:"next_after,omitempty"`
+	Hash      string `json:"hash,omitempty"`
+}
+
+type Filter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	RequestID  string
+	From, To   time.Time
+	// AfterSeq continues a listing after the entry with this sequence number.
+	AfterSeq int64
+	Limit    int
+}
+
+// PageSize is the number of entries a listing with f returns at most.
+func (f Filter) PageSize() int {
+	if f.Limit <= 0 {
+		return DefaultListLimit
+	}
+	return min(f.Limit, MaxListLimit)
+}
+
+// VerifyResult describes the hash chain of a tenant. BrokenAt is the sequence
+// number of the first entry whose hash does not match, or 0. Last is the last
+// entry found intact, to resume from.
+type VerifyResult struct {
+	Entries  int
+	Valid    bool
+	BrokenAt int64
+	Last     Checkpoint
+}
+
+// Checkpoint is an entry of a chain that was verified, with its hash. The
+// zero Checkpoint is the start of the chain.
+type Checkpoint struct {
+	Seq  int64
+	Hash string
+}
+
+// VerifyFilter selects the part of a chain a verification covers: up to
+// Limit entries following After.
+type VerifyFilter struct {
+	After Checkpoint
+	Limit int
+}
+
+// PageSize is the number of entries a verification with f covers at most.
+func (f VerifyFilter) PageSize() int {
+	if f.Limit <= 0 {
+		return DefaultVerifyLimit
+	}
+	return min(f.Limit, MaxVerifyLimit)
+}
+
+// Append records entry in tx, linking it to the chain head of its tenant.
+// Actor, request ID and trace ID are taken from ctx unless set. The caller
+// commits tx together with the change the entry describes.
+func Append(ctx context.Context, tx *sql.Tx, entry Entry) (Entry, error) {
+	if entry.TenantID == "" {
+		return Entry{}, ErrNoTenant
+	}
+	if entry.Actor == "" {
+		entry.Actor = actor(ctx)
+	}
+	if entry.RequestID == "" {
+		entry.RequestID = telemetry.RequestID(ctx)
+	}
+	if entry.TraceID == "" {
+		entry.TraceID = telemetry.TraceID(ctx)
+	}
+	// Postgres keeps microseconds; hashing the stored precision lets the chain
+	// be verified from the table.
+	entry.OccurredAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	changes, err := canonicalChanges(entry.Changes)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry.Changes = nil
+	if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+		return Entry{}, err
+	}
+
+	// Serialise writers of a tenant so that every entry links to the true
+	// chain head.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('audit_log'), hashtext($1))`, entry.TenantID); err != nil {
+		return Entry{}, err
+	}
+	row := tx.QueryRowContext(ctx, `SELECT hash FROM audit_log WHERE tenant_id = $1 ORDER BY seq DESC LIMIT 1`, entry.TenantID)
+	if err := row.Scan(&entry.PrevHash); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return Entry{}, err
+	}
+	if entry.Hash, err = Hash(entry); err != nil {
+		return Entry{}, err
+	}
+
+	row = tx.QueryRowContext(ctx, `
+		INSERT INTO audit_log (tenant_id, actor, action, entity_type, entity_id, changes, request_id, trace_id, occurred_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING seq
+	`, entry.TenantID, entry.Actor, entry.Action, entry.EntityType, entry.EntityID, changes,
+		entry.RequestID, entry.TraceID, entry.OccurredAt, entry.PrevHash, entry.Hash)
+	if err := row.Scan(&entry.Seq); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// Hash computes the chained hash of an entry. The sequence number is left
+// out: it is assigned by the database and gaps in it are harmless.
+func Hash(entry Entry) (string, error) {
+	changes, err := canonicalChanges(entry.Changes)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		entry.PrevHash,
+		entry.TenantID,
+		entry.Actor,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		string(changes),
+		entry.RequestID,
+		entry.TraceID,
+		entry.OccurredAt.UTC().Format(time.RFC3339Nano),
+	}, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalChanges encodes changes the same way whether they were built in
+// memory or read back from JSONB: map keys are sorted and values normalised to
+// their JSON form first.
+func canonicalChanges(changes map[string]Change) ([]byte, error) {
+	if changes == nil {
+		changes = map[string]Change{}
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+	var normalised map[string]Change
+	if err := json.Unmarshal(data, &normalised); err != nil {
+		return nil, err
+	}
+	return json.Marshal(normalised)
+}
+
+func actor(ctx context.Context) string {
+	if id := auth.PrincipalID(ctx); id != "" {
+		return id
+	}
+	return SystemActor
+}
+
+// Verifier checks a tenant's chain entry by entry, in sequence order, so that
+// long chains can be streamed from the database.
+type Verifier struct {
+	result VerifyResult
+}
+
+func NewVerifier() *Verifier {
+	return ResumeVerifier(Checkpoint{})
+}
+
+// ResumeVerifier continues a verification after the entry at from.
+func ResumeVerifier(from Checkpoint) *Verifier {
+	return &Verifier{result: VerifyResult{Valid: true, Last: from}}
+}
+
+// Add checks the next entry. Once the chain is broken further entries only
+// count towards the total.
+func (v *Verifier) Add(entry Entry) {
+	v.result.Entries++
+	if !v.result.Valid {
+		return
+	}
+	hash, err := Hash(entry)
+	if err != nil || entry.PrevHash != v.result.Last.Hash || entry.Hash != hash {
+		v.result.Valid = false
+		v.result.BrokenAt = entry.Seq
+		return
+	}
+	v.result.Last = Checkpoint{Seq: entry.Seq, Hash: entry.Hash}
+}
+
+func (v *Verifier) Result() VerifyResult {
+	return v.result
+}
@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after Fields
+		want          map[string]Change
+	}{
+		{
+			name:  "created",
+			after: Fields{"status": "CREATED"},
+			want:  map[string]Change{"status": {After: "CREATED"}},
+		},
+		{
+			name:   "deleted",
+			before: Fields{"url": "https://example.com"},
+			want:   map[string]Change{"url": {Before: "https://example.com"}},
+		},
+		{
+			name:   "only changed fields",
+			before: Fields{"status": "CREATED", "route": "ALMATY->ASTANA"},
+			after:  Fields{"status": "IN_TRANSIT", "route": "ALMATY->ASTANA"},
+			want:   map[string]Change{"status": {Before: "CREATED", After: "IN_TRANSIT"}},
+		},
+		{
+			name:   "unchanged slices",
+			before: Fields{"scopes": []string{"shipments:read"}},
+			after:  Fields{"scopes": []string{"shipments:read"}},
+			want:   map[string]Change{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashSurvivesJSONRoundTrip(t *testing.T) {
+	entry := testEntry("", Diff(nil, Fields{"price": 1500.5, "scopes": []string{"admin"}}))
+	want, err := Hash(entry)
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	// Entries read back from JSONB hold decoded JSON values, not Go ones.
+	data, err := json.Marshal(entry.Changes)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	entry.Changes = nil
+	if err := json.Unmarshal(data, &entry.Changes); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got, _ := Hash(entry); got != want {
+		t.Fatalf("Hash() after round trip = %s, want %s", got, want)
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	// lastSeq is the last entry left intact, 0 for none.
+	tests := []struct {
+		name    string
+		tamper  func(chain []Entry)
+		want    VerifyResult
+		lastSeq int64
+	}{
+		{name: "intact", tamper: func([]Entry) {}, want: VerifyResult{Entries: 3, Valid: true}, lastSeq: 3},
+		{
+			name:    "edited changes",
+			tamper:  func(chain []Entry) { chain[1].Changes = Diff(nil, Fields{"status": "DELIVERED"}) },
+			want:    VerifyResult{Entries: 3, BrokenAt: 2},
+			lastSeq: 1,
+		},
+		{
+			name:    "edited actor",
+			tamper:  func(chain []Entry) { chain[2].Actor = "someone-else" },
+			want:    VerifyResult{Entries: 3, BrokenAt: 3},
+			lastSeq: 2,
+		},
+		{
+			name: "deleted entry",
+			tamper: func(chain []Entry) {
+				copy(chain[1:], chain[2:])
+				chain[2] = Entry{}
+			},
+			want:    VerifyResult{Entries: 2, BrokenAt: 3},
+			lastSeq: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := testChain(t, 3)
+			tt.want.Last = Checkpoint{Seq: tt.lastSeq, Hash: chain[tt.lastSeq-1].Hash}
+			tt.tamper(chain)
+
+			verifier := NewVerifier()
+			for _, entry := range chain {
+				if entry.Seq != 0 {
+					verifier.Add(entry)
+				}
+			}
+			if got := verifier.Result(); got != tt.want {
+				t.Fatalf("Result() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumeVerifier(t *testing.T) {
+	chain := testChain(t, 4)
+	tests := []struct {
+		name string
+		from Checkpoint
+		want VerifyResult
+	}{
+		{
+			name: "from checkpoint",
+			from: Checkpoint{Seq: 2, Hash: chain[1].Hash},
+			want: VerifyResult{Entries: 2, Valid: true, Last: Checkpoint{Seq: 4, Hash: chain[3].Hash}},
+		},
+		{
+			name: "wrong checkpoint hash",
+			from: Checkpoint{Seq: 2, Hash: chain[0].Hash},
+			want: VerifyResult{Entries: 2, BrokenAt: 3, Last: Checkpoint{Seq: 2, Hash: chain[0].Hash}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := ResumeVerifier(tt.from)
+			for _, entry := range chain[tt.from.Seq:] {
+				verifier.Add(entry)
+			}
+			if got := verifier.Result(); got != tt.want {
+				t.Fatalf("Result() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testChain(t *testing.T, n int) []Entry {
+	t.Helper()
+	chain := make([]Entry, n)
+	prevHash := ""
+	for i := range chain {
+		entry := testEntry(prevHash, Diff(Fields{"status": "CREATED"}, Fields{"status": "IN_TRANSIT"}))
+		entry.Seq = int64(i + 1)
+		hash, err := Hash(entry)
+		if err != nil {
+			t.Fatalf("Hash() error = %v", err)
+		}
+		entry.Hash = hash
+		chain[i] = entry
+		prevHash = hash
+	}
+	return chain
+}
+
+func testEntry(prevHash string, changes map[string]Change) Entry {
+	return Entry{
+		TenantID:   "acme",
+		Actor:      "key-1",
+		Action:     "shipment.status_changed",
+		EntityType: "shipment",
+		EntityID:   "5f0c7a1e-8a43-4a53-9a43-3f1b1d0b7c11",
+		Changes:    changes,
+		RequestID:  "req-1",
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		OccurredAt: time.Date(2026, 3, 1, 12, 0, 0, 123456000, time.UTC),
+		PrevHash:   prevHash,
+	}
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenFileTTL is how long a token read from disk is reused. The file is
+// expected to be rewritten well before the token in it expires.
+const tokenFileTTL = 30 * time.Second
+
+// TokenFile sends the bearer token stored in a file with every gRPC call. The
+// file is kept fresh by whatever obtains service tokens from the SSO, so
+// tokens rotate without a restart.
+type TokenFile struct {
+	path string
+	now  func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	readAt time.Time
+}
+
+func NewTokenFile(path string) *TokenFile {
+	return &TokenFile{path: path, now: time.Now}
+}
+
+func (f *TokenFile) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := f.Token()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity allows plaintext connections inside the private
+// compose network.
+func (f *TokenFile) RequireTransportSecurity() bool {
+	return false
+}
+
+func (f *TokenFile) Token() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	if f.token != "" && now.Sub(f.readAt) < tokenFileTTL {
+		return f.token, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", f.path)
+	}
+
+	f.token, f.readAt = token, now
+	return token, nil
+}
@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// minRefetchInterval stops tokens with made-up key IDs from turning into
+	// a flood of JWKS requests.
+	minRefetchInterval = 30 * time.Second
+	maxJWKSSize        = 1 << 20
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySet holds the public keys from a JWKS document, read from a file or an
+// http(s) URL. Keys are reloaded every refresh interval, and early when a
+// token names a key ID that is not in the set, so a rotation at the identity
+// provider is picked up without a restart. Fetches run outside the lock and
+// are shared by all callers waiting for them.
+type KeySet struct {
+	source  string
+	refresh time.Duration
+	client  *http.Client
+	now     func() time.Time
+	fetches singleflight.Group
+
+	mu          sync.Mutex
+	keys        map[string]jwk
+	loadedAt    time.Time
+	attemptedAt time.Time
+}
+
+type jwk struct {
+	key crypto.PublicKey
+	alg string
+}
+
+func NewKeySet(source string, refresh time.Duration) *KeySet {
+	return &KeySet{
+		source:  source,
+		refresh: refresh,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		now:     time.Now,
+	}
+}
+
+// Load fetches the key set. Call it on startup to fail fast on a bad source.
+func (s *KeySet) Load(ctx context.Context) error {
+	return s.load(ctx)
+}
+
+func (s *KeySet) key(ctx context.Context, kid string) (jwk, error) {
+	now := s.now()
+	s.mu.Lock()
+	key, found := s.lookup(kid)
+	loaded := s.keys != nil
+	stale := now.Sub(s.loadedAt) >= s.refresh
+	reloadable := s.canReload(now)
+	s.mu.Unlock()
+
+	switch {
+	case !loaded:
+		if err := s.reload(ctx); err != nil {
+			return jwk{}, err
+		}
+	case !found && reloadable:
+		if s.reload(ctx) != nil {
+			return jwk{}, ErrUnknownKey
+		}
+	case stale && reloadable:
+		// Stale keys are served while a fetch in the background replaces
+		// them; a failed one keeps them, as the identity provider being down
+		// must not lock everybody out.
+		go s.reload(context.WithoutCancel(ctx))
+	}
+	if found {
+		return key, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	return jwk{}, ErrUnknownKey
+}
+
+// reload fetches the key set, joining a fetch already under way. The fetch
+// does not end with ctx, so a caller giving up does not fail it for the
+// others waiting on it; the HTTP client timeout bounds it instead.
+func (s *KeySet) reload(ctx context.Context) error {
+	result := s.fetches.DoChan("jwks", func() (any, error) {
+		return nil, s.load(context.WithoutCancel(ctx))
+	})
+	select {
+	case r := <-result:
+		return r.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *KeySet) canReload(now time.Time) bool {
+	return s.keys == nil || now.Sub(s.attemptedAt) >= minRefetchInterval
+}
+
+func (s *KeySet) lookup(kid string) (jwk, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// load fetches the key set and swaps it in. Only the swap holds the lock.
+func (s *KeySet) load(ctx context.Context) error {
+	s.mu.Lock()
+	attemptedAt := s.now()
+	s.attemptedAt = attemptedAt
+	s.mu.Unlock()
+
+	data, err := s.read(ctx)
+	if err != nil {
+		return fmt.Errorf("read jwks: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.loadedAt = attemptedAt
+	return nil
+}
+
+func (s *KeySet) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+// parseJWKS keeps the RSA and EC signing keys of a JWKS document and skips
+// everything else, so a provider publishing new key types does not break us.
+func parseJWKS(data []byte) (map[string]jwk, error) {
+	var doc struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]jwk, len(doc.Keys))
+	for _, raw := range doc.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch raw.Kty {
+		case "RSA":
+			key, err = parseRSAKey(raw.N, raw.E)
+		case "EC":
+			key, err = parseECKey(raw.Crv, raw.X, raw.Y)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %q: %w", raw.Kid, err)
+		}
+		keys[raw.Kid] = jwk{key: key, alg: raw.Alg}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no usable signing keys")
+	}
+	return keys, nil
+}
+
+func parseRSAKey(n, e string) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	if len(modulus) < 2048/8 {
+		return nil, errors.New("rsa key shorter than 2048 bits")
+	}
+	if len(exponent) == 0 || len(exponent) > 4 {
+		return nil, errors.New("invalid rsa exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
+
+func parseECKey(crv, x, y string) (*ecdsa.PublicKey, error) {
+	var (
+		curve elliptic.Curve
+		check ecdh.Curve
+	)
+	switch crv {
+	case "P-256":
+		curve, check = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, check = elliptic.P384(), ecdh.P384()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", crv)
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, err
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, err
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	if len(xBytes) != size || len(yBytes) != size {
+		return nil, errors.New("invalid ec coordinates")
+	}
+
+	// crypto/ecdh rejects points that are not on the curve.
+	point := append([]byte{4}, append(xBytes, yBytes...)...)
+	if _, err := check.NewPublicKey(point); err != nil {
+		return nil, err
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}, nil
+}
@@ -0,0 +1,261 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is how far our clock may drift from the identity provider's.
+const clockSkew = 30 * time.Second
+
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid bearer token")
+)
+
+type VerifierConfig struct {
+	// Issuer and Audience must match the iss and aud claims exactly.
+	Issuer   string
+	Audience string
+	// RolesClaim is the claim holding the user's roles. A dotted path reaches
+	// into nested objects, e.g. "realm_access.roles".
+	RolesClaim string
+	// CustomerClaim is the claim holding a merchant's customer ID.
+	CustomerClaim string
+	// TenantClaim is the claim holding the user's tenant.
+	TenantClaim string
+}
+
+// Verifier checks JWT bearer tokens issued by our SSO and turns them into
+// principals. Only asymmetric algorithms are accepted, so a leaked JWKS
+// cannot be used to mint tokens.
+type Verifier struct {
+	keys   *KeySet
+	config VerifierConfig
+	now    func() time.Time
+}
+
+func NewVerifier(keys *KeySet, config VerifierConfig) (*Verifier, error) {
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, errors.New("jwt issuer and audience are required")
+	}
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+	if config.CustomerClaim == "" {
+		config.CustomerClaim = "customer_id"
+	}
+	if config.TenantClaim == "" {
+		config.TenantClaim = "tenant_id"
+	}
+	return &Verifier{keys: keys, config: config, now: time.Now}, nil
+}
+
+// BearerToken extracts the token from an Authorization header value. It
+// returns "" if the value is not a bearer credential.
+func BearerToken(header string) string {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// Verify checks the token signature, issuer, audience and lifetime. Every
+// failure wraps ErrInvalidToken; the wrapped reason is meant for logs only.
+func (v *Verifier) Verify(ctx context.Context, token string) (Principal, error) {
+	if token == "" {
+		return Principal{}, ErrMissingToken
+	}
+
+	claims, err := v.verifySignature(ctx, token)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	return v.principal(claims), nil
+}
+
+func (v *Verifier) verifySignature(ctx context.Context, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("decode header: %w", err)
+	}
+	hash, ok := signingHashes[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported alg %q", header.Alg)
+	}
+
+	key, err := v.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.alg != "" && key.alg != header.Alg {
+		return nil, fmt.Errorf("key %q is not for alg %q", header.Kid, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+	digester := hash.New()
+	digester.Write([]byte(parts[0] + "." + parts[1]))
+	if err := verifyDigest(key.key, header.Alg, hash, digester.Sum(nil), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("decode claims: %w", err)
+	}
+	return claims, nil
+}
+
+var signingHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+}
+
+func verifyDigest(key crypto.PublicKey, alg string, hash crypto.Hash, digest, signature []byte) error {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("alg %q does not match an rsa key", alg)
+		}
+		return rsa.VerifyPKCS1v15(key, hash, digest, signature)
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || len(signature) != 2*size {
+			return fmt.Errorf("alg %q does not match an ec key", alg)
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("signature mismatch")
+		}
+		return nil
+	default:
+		return errors.New("unsupported key type")
+	}
+}
+
+func (v *Verifier) checkClaims(claims map[string]any) error {
+	if iss, _ := claims["iss"].(string); iss != v.config.Issuer {
+		return fmt.Errorf("unexpected issuer %q", iss)
+	}
+	if !slices.Contains(stringList(claims["aud"]), v.config.Audience) {
+		return errors.New("token is not for this audience")
+	}
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return errors.New("missing sub")
+	}
+
+	now := v.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("missing exp")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token not valid yet")
+	}
+	return nil
+}
+
+// principal grants the scopes of the user's roles plus any known scopes
+// listed in the scope claim, which is how machine clients are authorized.
+func (v *Verifier) principal(claims map[string]any) Principal {
+	sub := claims["sub"].(string)
+	principal := Principal{ID: "user:" + sub, Name: sub, Method: MethodJWT}
+	principal.CustomerID, _ = lookupClaim(claims, v.config.CustomerClaim).(string)
+	principal.TenantID, _ = lookupClaim(claims, v.config.TenantClaim).(string)
+	for _, claim := range []string{"preferred_username", "name"} {
+		if name, _ := claims[claim].(string); name != "" {
+			principal.Name = name
+			break
+		}
+	}
+
+	for _, value := range stringList(lookupClaim(claims, v.config.RolesClaim)) {
+		role := Role(value)
+		if _, ok := RoleScopes[role]; ok && !slices.Contains(principal.Roles, role) {
+			principal.Roles = append(principal.Roles, role)
+			principal.Scopes = append(principal.Scopes, RoleScopes[role]...)
+		}
+	}
+	for _, claim := range []string{"scope", "scp"} {
+		for _, value := range stringList(claims[claim]) {
+			if IsValidScope(Scope(value)) {
+				principal.Scopes = append(principal.Scopes, Scope(value))
+			}
+		}
+	}
+
+	slices.Sort(principal.Scopes)
+	principal.Scopes = slices.Compact(principal.Scopes)
+	return principal
+}
+
+func lookupClaim(claims map[string]any, path string) any {
+	var value any = claims
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+// stringList reads a claim that is either a JSON array of strings or a
+// space-separated string, the two shapes used for aud, scope and roles.
+func stringList(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			if item, ok := item.(string); ok {
+				items = append(items, item)
+			}
+		}
+		return items
+	default:
+		return nil
+	}
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type testSigner struct {
+	kid string
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newRSASigner(t *testing.T, kid string) testSigner {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return testSigner{kid: kid, rsa: key}
+}
+
+func newECSigner(t *testing.T, kid string) testSigner {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return testSigner{kid: kid, ec: key}
+}
+
+func (s testSigner) jwk() map[string]string {
+	encode := base64.RawURLEncoding.EncodeToString
+	if s.rsa != nil {
+		return map[string]string{
+			"kid": s.kid, "kty": "RSA", "use": "sig",
+			"n": encode(s.rsa.N.Bytes()),
+			"e": encode(big.NewInt(int64(s.rsa.E)).Bytes()),
+		}
+	}
+	public, _ := s.ec.PublicKey.ECDH()
+	point := public.Bytes()
+	return map[string]string{
+		"kid": s.kid, "kty": "EC", "crv": "P-256",
+		"x": encode(point[1:33]), "y": encode(point[33:]),
+	}
+}
+
+func (s testSigner) sign(t *testing.T, alg string, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": s.kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(input))
+
+	var signature []byte
+	var err error
+	if s.rsa != nil {
+		signature, err = rsa.SignPKCS1v15(rand.Reader, s.rsa, crypto.SHA256, digest.Sum(nil))
+	} else {
+		var r, sig *big.Int
+		r, sig, err = ecdsa.Sign(rand.Reader, s.ec, digest.Sum(nil))
+		signature = append(r.FillBytes(make([]byte, 32)), sig.FillBytes(make([]byte, 32))...)
+	}
+	if err != nil {
+		t.Fatalf("sign error = %v", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJWKS(t *testing.T, path string, signers ...testSigner) {
+	keys := make([]map[string]string, 0, len(signers))
+	for _, signer := range signers {
+		keys = append(keys, signer.jwk())
+	}
+	data, _ := json.Marshal(map[string]any{"keys": keys})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func newTestVerifier(t *testing.T, path string) *Verifier {
+	keys := NewKeySet(path, time.Hour)
+	keys.now = func() time.Time { return testNow }
+	verifier, err := NewVerifier(keys, VerifierConfig{Issuer: "https://sso.example", Audience: "shipments", RolesClaim: "realm_access.roles"})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+	verifier.now = func() time.Time { return testNow }
+	return verifier
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":                "https://sso.example",
+		"aud":                []string{"account", "shipments"},
+		"sub":                "u-1",
+		"preferred_username": "aigerim",
+		"exp":                testNow.Add(time.Minute).Unix(),
+		"realm_access":       map[string]any{"roles": []string{"auditor", "offline_access"}},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	rsaSigner, ecSigner := newRSASigner(t, "rsa-1"), newECSigner(t, "ec-1")
+	writeJWKS(t, path, rsaSigner, ecSigner)
+	verifier := newTestVerifier(t, path)
+
+	with := func(key string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{name: "rsa", token: rsaSigner.sign(t, "RS256", validClaims())},
+		{name: "ec", token: ecSigner.sign(t, "ES256", validClaims())},
+		{name: "within clock skew", token: rsaSigner.sign(t, "RS256", with("exp", testNow.Add(-10*time.Second).Unix()))},
+		{name: "missing", token: "", err: ErrMissingToken},
+		{name: "malformed", token: "not-a-jwt", err: ErrInvalidToken},
+		{name: "wrong issuer", token: rsaSigner.sign(t, "RS256", with("iss", "https://evil.example")), err: ErrInvalidToken},
+		{name: "wrong audience", token: rsaSigner.sign(t, "RS256", with("aud", "billing")), err: ErrInvalidToken},
+		{name: "expired", token: rsaSigner.sign(t, "RS256", with("exp", testNow.Add(-time.Minute).Unix())), err: ErrInvalidToken},
+		{name: "no expiry", token: rsaSigner.sign(t, "RS256", with("exp", nil)), err: ErrInvalidToken},
+		{name: "not yet valid", token: rsaSigner.sign(t, "RS256", with("nbf", testNow.Add(time.Minute).Unix())), err: ErrInvalidToken},
+		{name: "alg none", token: rsaSigner.sign(t, "none", validClaims()), err: ErrInvalidToken},
+		{name: "alg mismatch", token: ecSigner.sign(t, "RS256", validClaims()), err: ErrInvalidToken},
+		{name: "unknown key", token: newRSASigner(t, "rsa-2").sign(t, "RS256", validClaims()), err: ErrInvalidToken},
+		{name: "forged key id", token: testSigner{kid: "rsa-1", rsa: newRSASigner(t, "").rsa}.sign(t, "RS256", validClaims()), err: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(context.Background(), tt.token)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestVerifyMapsClaims(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	signer := newRSASigner(t, "rsa-1")
+	writeJWKS(t, path, signer)
+
+	claims := validClaims()
+	claims["scope"] = "openid customers:write"
+	claims["customer_id"] = "c-1"
+	claims["tenant_id"] = "brand-a"
+	principal, err := newTestVerifier(t, path).Verify(context.Background(), signer.sign(t, "RS256", claims))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if principal.ID != "user:u-1" || principal.Name != "aigerim" || principal.Method != MethodJWT || principal.CustomerID != "c-1" || principal.TenantID != "brand-a" {
+		t.Fatalf("Verify() principal = %+v", principal)
+	}
+	if !slices.Equal(principal.Roles, []Role{RoleAuditor}) {
+		t.Fatalf("Verify() roles = %v, want [auditor]", principal.Roles)
+	}
+	want := []Scope{ScopeAuditRead, ScopeCustomersRead, ScopeCustomersWrite, ScopeShipmentsRead}
+	if !slices.Equal(principal.Scopes, want) {
+		t.Fatalf("Verify() scopes = %v, want %v", principal.Scopes, want)
+	}
+}
+
+func TestKeySetPicksUpRotatedKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	oldSigner, newSigner := newRSASigner(t, "old"), newRSASigner(t, "new")
+	writeJWKS(t, path, oldSigner)
+	verifier := newTestVerifier(t, path)
+
+	if _, err := verifier.Verify(context.Background(), oldSigner.sign(t, "RS256", validClaims())); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	writeJWKS(t, path, newSigner)
+	token := newSigner.sign(t, "RS256", validClaims())
+	if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify() error = %v, want a refetch to be throttled", err)
+	}
+
+	now := testNow.Add(minRefetchInterval)
+	verifier.keys.now = func() time.Time { return now }
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("Verify() error = %v after rotation", err)
+	}
+}
+
+func TestKeySetServesStaleKeysWhileFetching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	signer := newRSASigner(t, "rsa-1")
+	writeJWKS(t, path, signer)
+
+	release := make(chan struct{})
+	blocked := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if blocked {
+			<-release
+		}
+		http.ServeFile(w, r, path)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	keys := NewKeySet(server.URL, time.Minute)
+	keys.now = func() time.Time { return testNow }
+	if err := keys.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	blocked = true
+	keys.now = func() time.Time { return testNow.Add(time.Hour) }
+	done := make(chan error, 1)
+	go func() {
+		_, err := keys.key(context.Background(), "rsa-1")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("key() error = %v, want the stale key", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("key() waited for the JWKS fetch")
+	}
+}
@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/platform/tenant"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// StatusHeader is the gRPC response header through which a handler can pick
+// the HTTP status code of a successful REST response, e.g. 201 on create.
+const StatusHeader = "x-http-code"
+
+// NewMux returns a gateway mux that renders messages with their proto field
+// names and always emits unpopulated fields, so REST and gRPC share one
+// shape.
+func NewMux() *runtime.ServeMux {
+	return runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &runtime.JSONPb{
+				MarshalOptions: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+			},
+		}),
+		runtime.WithForwardResponseOption(forwardStatus),
+		runtime.WithIncomingHeaderMatcher(matchHeader),
+	)
+}
+
+// matchHeader forwards the caller's API key so the gRPC server can check it
+// again, the requested tenant and the request ID; Authorization is forwarded
+// by the gateway itself.
+func matchHeader(key string) (string, bool) {
+	if strings.EqualFold(key, auth.APIKeyHeader) || strings.EqualFold(key, tenant.MetadataKey) || strings.EqualFold(key, telemetry.RequestIDHeader) {
+		return strings.ToLower(key), true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// SetStatus asks the gateway to answer the current call with code. It is a
+// no-op for plain gRPC clients apart from an extra response header.
+func SetStatus(ctx context.Context, code int) error {
+	return grpc.SetHeader(ctx, metadata.Pairs(StatusHeader, strconv.Itoa(code)))
+}
+
+func forwardStatus(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+	md, ok := runtime.ServerMetadataFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	values := md.HeaderMD.Get(StatusHeader)
+	if len(values) == 0 {
+		return nil
+	}
+	code, err := strconv.Atoi(values[0])
+	if err != nil {
+		return err
+	}
+
+	delete(md.HeaderMD, StatusHeader)
+	w.Header().Del(runtime.MetadataHeaderPrefix + StatusHeader)
+	w.WriteHeader(code)
+	return nil
+}
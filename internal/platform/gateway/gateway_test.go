@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestForwardStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		header metadata.MD
+		want   int
+	}{
+		{name: "no metadata", want: http.StatusOK},
+		{name: "created", header: metadata.Pairs(StatusHeader, "201"), want: http.StatusCreated},
+		{name: "accepted", header: metadata.Pairs(StatusHeader, "202"), want: http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != nil {
+				ctx = runtime.NewServerMetadataContext(ctx, runtime.ServerMetadata{HeaderMD: tt.header})
+			}
+
+			recorder := httptest.NewRecorder()
+			recorder.Header().Set(runtime.MetadataHeaderPrefix+StatusHeader, "201")
+			if err := forwardStatus(ctx, recorder, nil); err != nil {
+				t.Fatalf("forwardStatus() error = %v", err)
+			}
+
+			if recorder.Code != tt.want {
+				t.Fatalf("forwardStatus() code = %d, want %d", recorder.Code, tt.want)
+			}
+			if tt.header != nil && recorder.Header().Get(runtime.MetadataHeaderPrefix+StatusHeader) != "" {
+				t.Fatalf("forwardStatus() leaked the %s header", StatusHeader)
+			}
+		})
+	}
+}
+
+func TestForwardStatusRejectsGarbage(t *testing.T) {
+	ctx := runtime.NewServerMetadataContext(context.Background(), runtime.ServerMetadata{
+		HeaderMD: metadata.Pairs(StatusHeader, "created"),
+	})
+
+	if err := forwardStatus(ctx, httptest.NewRecorder(), nil); err == nil {
+		t.Fatalf("forwardStatus() error = nil, want parse error")
+	}
+}
+
+func TestMatchHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{header: "X-Api-Key", want: "x-api-key", ok: true},
+		{header: "X-Tenant-Id", want: "x-tenant-id", ok: true},
+		{header: "X-Request-Id", want: "x-request-id", ok: true},
+		{header: "Accept-Language", want: runtime.MetadataPrefix + "Accept-Language", ok: true},
+		{header: "X-Custom", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, ok := matchHeader(tt.header)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("matchHeader(%q) = %q, %v, want %q, %v", tt.header, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+var (
+	ErrNotConnected   = errors.New("grpc connection is not ready")
+	ErrSchemaOutdated = errors.New("database schema is outdated")
+)
+
+// GRPCConn checks that conn is connected. An idle connection is woken up and
+// given until the end of the check to connect.
+func GRPCConn(conn *grpc.ClientConn) Check {
+	return func(ctx context.Context) error {
+		for {
+			state := conn.GetState()
+			switch state {
+			case connectivity.Ready:
+				return nil
+			case connectivity.Idle:
+				conn.Connect()
+			case connectivity.Shutdown:
+				return fmt.Errorf("%w: %s", ErrNotConnected, state)
+			}
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("%w: %s", ErrNotConnected, state)
+			}
+		}
+	}
+}
+
+// SchemaVersion checks that the migrations applied to db, as recorded in
+// schema_migrations, reach at least version required. Newer schemas pass:
+// migrations are kept compatible with the release before them.
+func SchemaVersion(db *sql.DB, required int) Check {
+	return func(ctx context.Context) error {
+		var version sql.NullInt64
+		if err := db.QueryRowContext(ctx, `SELECT max(version) FROM schema_migrations`).Scan(&version); err != nil {
+			return err
+		}
+		if version.Int64 < int64(required) {
+			return fmt.Errorf("%w: version %d, need %d", ErrSchemaOutdated, version.Int64, required)
+		}
+		return nil
+	}
+}
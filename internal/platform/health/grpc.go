@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultInterval is how often SyncGRPC runs the checks.
+const DefaultInterval = 5 * time.Second
+
+// SyncGRPC runs the checks of c every interval and reports the outcome
+// through the grpc.health.v1 service of server, for the overall server ("")
+// and each of services. It returns when ctx ends; the caller shuts server
+// down, which reports NOT_SERVING to watchers while the service drains.
+func (c *Checker) SyncGRPC(ctx context.Context, server *grpchealth.Server, interval time.Duration, logger *slog.Logger, services ...string) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	current := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		report := c.Run(ctx)
+		status := healthpb.HealthCheckResponse_SERVING
+		if !report.Ready() {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		if status != current && ctx.Err() == nil {
+			logger.Info(
+				"health_status_changed",
+				slog.String("status", status.String()),
+				slog.Any("failed_checks", report.Failed()),
+			)
+			for _, service := range append([]string{""}, services...) {
+				server.SetServingStatus(service, status)
+			}
+			current = status
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
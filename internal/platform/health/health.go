@@ -0,0 +1,184 @@
+// Package health answers the liveness and readiness probes of both services.
+// Liveness only says the process is serving; readiness runs the checks of the
+// dependencies a service needs to do its work.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+	// StatusDegraded reports a ready service whose optional checks fail.
+	StatusDegraded = "degraded"
+)
+
+// DefaultTimeout bounds a run of the checks, so that a hanging dependency
+// fails the probe instead of stalling it.
+const DefaultTimeout = 2 * time.Second
+
+// Check returns nil if the dependency it looks at is usable.
+type Check func(ctx context.Context) error
+
+type check struct {
+	name     string
+	run      Check
+	optional bool
+}
+
+// Checker runs named checks concurrently.
+type Checker struct {
+	timeout time.Duration
+	checks  []check
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a check the service cannot be ready without.
+func (c *Checker) Add(name string, run Check) {
+	c.checks = append(c.checks, check{name: name, run: run})
+}
+
+// AddOptional registers a check whose failure is reported but leaves the
+// service ready, e.g. for a dependency it can work around.
+func (c *Checker) AddOptional(name string, run Check) {
+	c.checks = append(c.checks, check{name: name, run: run, optional: true})
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status     string  `json:"status"`
+	Optional   bool    `json:"optional,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of all checks, as served by the readiness probe.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// Ready reports whether no required check failed.
+func (r Report) Ready() bool {
+	return r.Status != StatusFail
+}
+
+// Failed lists the checks that failed, required or not.
+func (r Report) Failed() []string {
+	var names []string
+	for name, result := range r.Checks {
+		if result.Status == StatusFail {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Run executes all checks. Checks still running when the timeout expires are
+// reported as failed and left to finish on their own.
+func (c *Checker) Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	type outcome struct {
+		index  int
+		result Result
+	}
+	outcomes := make(chan outcome, len(c.checks))
+	started := time.Now()
+	for i, check := range c.checks {
+		go func() {
+			outcomes <- outcome{index: i, result: run(ctx, check)}
+		}()
+	}
+
+	results := make([]*Result, len(c.checks))
+collect:
+	for range c.checks {
+		select {
+		case o := <-outcomes:
+			results[o.index] = &o.result
+		case <-ctx.Done():
+			break collect
+		}
+	}
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(c.checks))}
+	for i, check := range c.checks {
+		result := results[i]
+		if result == nil {
+			result = &Result{
+				Status:     StatusFail,
+				Optional:   check.optional,
+				DurationMS: milliseconds(time.Since(started)),
+				Error:      ctx.Err().Error(),
+			}
+		}
+		report.Checks[check.name] = *result
+		switch {
+		case result.Status == StatusOK:
+		case !check.optional:
+			report.Status = StatusFail
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func run(ctx context.Context, check check) Result {
+	started := time.Now()
+	err := check.run(ctx)
+	result := Result{
+		Status:     StatusOK,
+		Optional:   check.optional,
+		DurationMS: milliseconds(time.Since(started)),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// ReadinessHandler serves the report of c as JSON, with status 503 unless the
+// service is ready.
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		statusCode := http.StatusOK
+		if !report.Ready() {
+			statusCode = http.StatusServiceUnavailable
+		}
+		writeJSON(w, statusCode, report)
+	})
+}
+
+// LivenessHandler answers as long as the process serves HTTP. It checks no
+// dependencies: restarting the service would not bring them back.
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, Report{Status: StatusOK})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckerRun(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	stuck := func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		required map[string]Check
+		optional map[string]Check
+		want     string
+		failed   string
+	}{
+		{name: "no checks", want: StatusOK},
+		{name: "all pass", required: map[string]Check{"postgres": ok}, optional: map[string]Check{"customer": ok}, want: StatusOK},
+		{name: "required fails", required: map[string]Check{"postgres": down, "schema": ok}, want: StatusFail, failed: "postgres"},
+		{name: "optional fails", required: map[string]Check{"postgres": ok}, optional: map[string]Check{"customer": down}, want: StatusDegraded, failed: "customer"},
+		{name: "both fail", required: map[string]Check{"postgres": down}, optional: map[string]Check{"customer": down}, want: StatusFail},
+		{name: "times out", required: map[string]Check{"postgres": hang}, want: StatusFail, failed: "postgres"},
+		{name: "ignores timeout", required: map[string]Check{"postgres": stuck}, want: StatusFail, failed: "postgres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(50 * time.Millisecond)
+			for name, check := range tt.required {
+				checker.Add(name, check)
+			}
+			for name, check := range tt.optional {
+				checker.AddOptional(name, check)
+			}
+
+			started := time.Now()
+			report := checker.Run(context.Background())
+			if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+				t.Fatalf("Run() took %v, want it bounded by the timeout", elapsed)
+			}
+
+			if report.Status != tt.want {
+				t.Fatalf("Run() status = %q, want %q", report.Status, tt.want)
+			}
+			if got := len(report.Checks); got != len(tt.required)+len(tt.optional) {
+				t.Fatalf("Run() checks = %d, want %d", got, len(tt.required)+len(tt.optional))
+			}
+			if tt.failed != "" {
+				result := report.Checks[tt.failed]
+				if result.Status != StatusFail || result.Error == "" {
+					t.Fatalf("Run() %s = %+v, want failed with an error", tt.failed, result)
+				}
+			}
+		})
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    Check
+		optional bool
+		want     int
+	}{
+		{name: "ready", check: func(context.Context) error { return nil }, want: http.StatusOK},
+		{name: "not ready", check: func(context.Context) error { return errors.New("down") }, want: http.StatusServiceUnavailable},
+		{name: "degraded", check: func(context.Context) error { return errors.New("down") }, optional: true, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(time.Second)
+			if tt.optional {
+				checker.AddOptional("customer", tt.check)
+			} else {
+				checker.Add("postgres", tt.check)
+			}
+
+			recorder := httptest.NewRecorder()
+			checker.ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if recorder.Code != tt.want {
+				t.Fatalf("ReadinessHandler() status = %d, want %d", recorder.Code, tt.want)
+			}
+			if got := recorder.Header().Get("Content-Type"); got != "application/json" {
+				t.Fatalf("ReadinessHandler() Content-Type = %q, want application/json", got)
+			}
+			var report Report
+			if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+				t.Fatalf("ReadinessHandler() body error = %v", err)
+			}
+			if len(report.Checks) != 1 {
+				t.Fatalf("ReadinessHandler() checks = %v, want one", report.Checks)
+			}
+		})
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	LivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("LivenessHandler() status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if got, want := recorder.Body.String(), "{\"status\":\"ok\"}\n"; got != want {
+		t.Fatalf("LivenessHandler() body = %q, want %q", got, want)
+	}
+}
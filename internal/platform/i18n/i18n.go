@@ -0,0 +1,102 @@
+package i18n
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+type Language string
+
+const (
+	English Language = "en"
+	Russian Language = "ru"
+	Kazakh  Language = "kk"
+)
+
+// Default is used when the client accepts none of the supported languages.
+const Default = English
+
+// Supported lists the languages every catalogue entry must be translated to.
+var Supported = []Language{English, Russian, Kazakh}
+
+// Text is one message in every supported language.
+type Text map[Language]string
+
+// In returns the message in lang, falling back to Default.
+func (t Text) In(lang Language) string {
+	if message, ok := t[lang]; ok {
+		return message
+	}
+	return t[Default]
+}
+
+// Catalog maps stable error codes to their messages.
+type Catalog map[string]Text
+
+// Message returns the message for code in lang. Unknown codes yield "".
+func (c Catalog) Message(code string, lang Language) string {
+	return c[code].In(lang)
+}
+
+// Missing lists "code/lang" for every translation the catalog lacks.
+func (c Catalog) Missing() []string {
+	var missing []string
+	for code, text := range c {
+		for _, lang := range Supported {
+			if text[lang] == "" {
+				missing = append(missing, code+"/"+string(lang))
+			}
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+// Negotiate picks the supported language the client prefers most from an
+// Accept-Language value such as "kk-KZ, ru;q=0.8". Regional variants match
+// their base language and "kz", a common mistake for Kazakh, is accepted.
+func Negotiate(acceptLanguage string) Language {
+	best, bestQuality := Default, 0.0
+	for _, item := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		lang, ok := match(tag)
+		if ok && quality > bestQuality {
+			best, bestQuality = lang, quality
+		}
+	}
+	return best
+}
+
+// FromIncomingContext negotiates the language of a gRPC call from its
+// accept-language metadata, also as forwarded by grpc-gateway.
+func FromIncomingContext(ctx context.Context) Language {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{"accept-language", "grpcgateway-accept-language"} {
+		if values := md.Get(key); len(values) > 0 {
+			return Negotiate(strings.Join(values, ","))
+		}
+	}
+	return Default
+}
+
+func match(tag string) (Language, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	if base == "kz" {
+		return Kazakh, true
+	}
+	lang := Language(base)
+	return lang, slices.Contains(Supported, lang)
+}
@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Language
+	}{
+		{header: "", want: Default},
+		{header: "ru", want: Russian},
+		{header: "kk-KZ", want: Kazakh},
+		{header: "kz", want: Kazakh},
+		{header: "de-DE, ru;q=0.5, en;q=0.4", want: Russian},
+		{header: "en;q=0.3, kk;q=0.9", want: Kazakh},
+		{header: "*", want: Default},
+		{header: "ru;q=bogus, kk;q=0.1", want: Kazakh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := Negotiate(tt.header); got != tt.want {
+				t.Fatalf("Negotiate(%q) = %s, want %s", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromIncomingContext(t *testing.T) {
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want Language
+	}{
+		{name: "none", want: Default},
+		{name: "grpc client", md: metadata.Pairs("accept-language", "ru-RU"), want: Russian},
+		{name: "gateway", md: metadata.Pairs("grpcgateway-accept-language", "kk"), want: Kazakh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			if got := FromIncomingContext(ctx); got != tt.want {
+				t.Fatalf("FromIncomingContext() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogFallsBackToDefault(t *testing.T) {
+	catalog := Catalog{"not_found": {English: "Not found"}}
+
+	if got := catalog.Message("not_found", Kazakh); got != "Not found" {
+		t.Fatalf("Message() = %q, want the default translation", got)
+	}
+	if got := catalog.Missing(); len(got) != 2 {
+		t.Fatalf("Missing() = %v, want ru and kk", got)
+	}
+}
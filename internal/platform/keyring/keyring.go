@@ -0,0 +1,180 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	keySize     = 32
+	nonceSize   = 12
+	tagSize     = 16
+	blobVersion = 1
+)
+
+var (
+	ErrUnknownKey          = errors.New("unknown encryption key")
+	ErrMalformedCiphertext = errors.New("malformed ciphertext")
+)
+
+// Keyring holds the key-encryption keys used for envelope encryption and the
+// HMAC key used to build blind indexes. Only the primary key encrypts; every
+// key in the ring can decrypt, which is what makes rotation possible.
+type Keyring struct {
+	primary  string
+	keys     map[string][]byte
+	indexKey []byte
+}
+
+type keyFile struct {
+	Primary  string            `json:"primary"`
+	Keys     map[string]string `json:"keys"`
+	IndexKey string            `json:"index_key"`
+}
+
+// Load reads a JSON key file of the form
+//
+//	{"primary": "k2", "keys": {"k1": "<base64>", "k2": "<base64>"}, "index_key": "<base64>"}
+//
+// where every key is 32 random bytes.
+func Load(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse key file: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+
+	indexKey, err := decodeKey(file.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("index key: %w", err)
+	}
+
+	return New(file.Primary, keys, indexKey)
+}
+
+func New(primary string, keys map[string][]byte, indexKey []byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q: %w", primary, ErrUnknownKey)
+	}
+	if len(indexKey) != keySize {
+		return nil, fmt.Errorf("index key must be %d bytes", keySize)
+	}
+	return &Keyring{primary: primary, keys: keys, indexKey: indexKey}, nil
+}
+
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// Encrypt seals plaintext under a fresh data key and wraps the data key with
+// the primary key. It returns the primary key ID alongside the blob.
+func (k *Keyring) Encrypt(plaintext []byte) (string, []byte, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", nil, err
+	}
+
+	wrapped, err := seal(k.keys[k.primary], dataKey, []byte(k.primary))
+	if err != nil {
+		return "", nil, err
+	}
+	sealed, err := seal(dataKey, plaintext, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	blob := make([]byte, 0, 1+len(wrapped)+len(sealed))
+	blob = append(blob, blobVersion)
+	blob = append(blob, wrapped...)
+	blob = append(blob, sealed...)
+	return k.primary, blob, nil
+}
+
+func (k *Keyring) Decrypt(keyID string, blob []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key %q: %w", keyID, ErrUnknownKey)
+	}
+
+	wrappedSize := nonceSize + keySize + tagSize
+	if len(blob) < 1+wrappedSize+nonceSize+tagSize || blob[0] != blobVersion {
+		return nil, ErrMalformedCiphertext
+	}
+
+	dataKey, err := open(key, blob[1:1+wrappedSize], []byte(keyID))
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, blob[1+wrappedSize:], nil)
+}
+
+// BlindIndex returns a keyed hash of value that is stable across encryption
+// key rotations, so it can back equality lookups and unique constraints.
+func (k *Keyring) BlindIndex(value string) []byte {
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < nonceSize {
+		return nil, ErrMalformedCiphertext
+	}
+	return aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("must be %d bytes", keySize)
+	}
+	return key, nil
+}
@@ -0,0 +1,63 @@
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	ring, err := New("k1", map[string][]byte{"k1": testKey(1)}, testKey(9))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	keyID, blob, err := ring.Encrypt([]byte("990101123456"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if keyID != "k1" {
+		t.Fatalf("Encrypt() key id = %q, want k1", keyID)
+	}
+	if bytes.Contains(blob, []byte("990101123456")) {
+		t.Fatal("Encrypt() blob contains plaintext")
+	}
+
+	got, err := ring.Decrypt(keyID, blob)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(got) != "990101123456" {
+		t.Fatalf("Decrypt() = %q", got)
+	}
+
+	blob[len(blob)-1] ^= 0xff
+	if _, err := ring.Decrypt(keyID, blob); err == nil {
+		t.Fatal("Decrypt() of tampered blob succeeded")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	oldRing, _ := New("k1", map[string][]byte{"k1": testKey(1)}, testKey(9))
+	newRing, _ := New("k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)}, testKey(9))
+
+	keyID, blob, err := oldRing.Encrypt([]byte("990101123456"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if _, err := newRing.Decrypt(keyID, blob); err != nil {
+		t.Fatalf("Decrypt() with rotated ring error = %v", err)
+	}
+	if !bytes.Equal(oldRing.BlindIndex("990101123456"), newRing.BlindIndex("990101123456")) {
+		t.Fatal("BlindIndex() changed across key rotation")
+	}
+
+	retired, _ := New("k2", map[string][]byte{"k2": testKey(2)}, testKey(9))
+	if _, err := retired.Decrypt(keyID, blob); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Decrypt() error = %v, want %v", err, ErrUnknownKey)
+	}
+}
@@ -0,0 +1,196 @@
+package mtls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	devCAValidity   = 10 * 365 * 24 * time.Hour
+	devLeafValidity = 30 * 24 * time.Hour
+)
+
+// Dev issues a throwaway certificate for sans, signed by a development CA kept
+// in dir, and returns a ModeMutual configuration for it. The first process to
+// run creates the CA; every process pointed at the same dir trusts the
+// others. The CA key lies next to the certificates, so this is for local
+// testing only.
+func Dev(dir string, sans []string) (Config, error) {
+	if len(sans) == 0 {
+		return Config{}, errors.New("dev certificate needs at least one san")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Config{}, err
+	}
+
+	caCert, caKey, err := devCA(dir)
+	if err != nil {
+		return Config{}, fmt.Errorf("dev ca: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return Config{}, err
+	}
+	template, err := certTemplate(sans[0], devLeafValidity)
+	if err != nil {
+		return Config{}, err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	for _, san := range sans {
+		if ip := net.ParseIP(san); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, san)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, key.Public(), caKey)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Config{
+		Mode:     ModeMutual,
+		CertFile: filepath.Join(dir, sans[0]+".pem"),
+		KeyFile:  filepath.Join(dir, sans[0]+"-key.pem"),
+		CAFile:   filepath.Join(dir, "ca", "ca.pem"),
+	}
+	if err := writeKey(cfg.KeyFile, key); err != nil {
+		return Config{}, err
+	}
+	if err := writePEM(cfg.CertFile, "CERTIFICATE", der, 0o644); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// devCA loads the CA in dir/ca, creating it if needed. The CA is written to a
+// temporary directory and renamed into place, so processes starting together
+// agree on a single CA.
+func devCA(dir string) (*x509.Certificate, crypto.Signer, error) {
+	caDir := filepath.Join(dir, "ca")
+	cert, key, err := readCA(caDir)
+	if !errors.Is(err, os.ErrNotExist) {
+		return cert, key, err
+	}
+
+	tmp, err := os.MkdirTemp(dir, ".ca-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template, err := certTemplate("shipment-customer-service dev CA", devCAValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	der, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := writeKey(filepath.Join(tmp, "ca-key.pem"), caKey); err != nil {
+		return nil, nil, err
+	}
+	if err := writePEM(filepath.Join(tmp, "ca.pem"), "CERTIFICATE", der, 0o644); err != nil {
+		return nil, nil, err
+	}
+
+	// Losing the race to another process is fine: its CA is used instead.
+	_ = os.Rename(tmp, caDir)
+	return readCA(caDir)
+}
+
+func readCA(dir string) (*x509.Certificate, crypto.Signer, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, "ca-key.pem"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, fmt.Errorf("malformed dev ca in %s", dir)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("dev ca key in %s cannot sign", dir)
+	}
+	return cert, signer, nil
+}
+
+func certTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}, nil
+}
+
+func writeKey(path string, key crypto.Signer) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return writePEM(path, "PRIVATE KEY", der, 0o600)
+}
+
+// writePEM replaces path atomically, so a process reloading the file never
+// sees it half-written.
+func writePEM(path, blockType string, der []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := pem.Encode(tmp, &pem.Block{Type: blockType, Bytes: der}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,151 @@
+package mtls
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+func TestHandshakeAuthorizesPeers(t *testing.T) {
+	dir := t.TempDir()
+	customer := devFiles(t, dir, []string{"customer-service"}, []string{"shipment-service"})
+
+	tests := []struct {
+		name    string
+		client  []string
+		peers   []string
+		wantErr bool
+	}{
+		{name: "allowed client", client: []string{"shipment-service"}, peers: []string{"customer-service"}},
+		{name: "client not in peers", client: []string{"reporting-service"}, peers: []string{"customer-service"}, wantErr: true},
+		{name: "server not in peers", client: []string{"shipment-service"}, peers: []string{"billing-service"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := devFiles(t, dir, tt.client, tt.peers)
+			_, err := handshake(t, customer.ServerCredentials(), client.ClientCredentials("customer-service"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handshake() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandshakeRejectsForeignCA(t *testing.T) {
+	customer := devFiles(t, t.TempDir(), []string{"customer-service"}, nil)
+	client := devFiles(t, t.TempDir(), []string{"shipment-service"}, nil)
+
+	if _, err := handshake(t, customer.ServerCredentials(), client.ClientCredentials("customer-service")); err == nil {
+		t.Fatalf("handshake() error = nil, want certificate error")
+	}
+}
+
+func TestFilesReloadRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	customer := devFiles(t, dir, []string{"customer-service"}, nil)
+	client := devFiles(t, dir, []string{"shipment-service"}, nil)
+
+	now := time.Now()
+	customer.now = func() time.Time { return now }
+
+	if _, err := Dev(dir, []string{"customer-service", "customer-v2"}); err != nil {
+		t.Fatalf("Dev() error = %v", err)
+	}
+	state, err := handshake(t, customer.ServerCredentials(), client.ClientCredentials("customer-service"))
+	if err != nil {
+		t.Fatalf("handshake() error = %v", err)
+	}
+	if sans := peerSANs(state.State.PeerCertificates[0]); slices.Contains(sans, "customer-v2") {
+		t.Fatalf("served sans = %v before the reload interval", sans)
+	}
+
+	now = now.Add(time.Minute)
+	state, err = handshake(t, customer.ServerCredentials(), client.ClientCredentials("customer-service"))
+	if err != nil {
+		t.Fatalf("handshake() error = %v", err)
+	}
+	if sans := peerSANs(state.State.PeerCertificates[0]); !slices.Contains(sans, "customer-v2") {
+		t.Fatalf("served sans = %v, want the rotated certificate", sans)
+	}
+}
+
+func devFiles(t *testing.T, dir string, sans, peers []string) *Files {
+	t.Helper()
+	cfg, err := Dev(dir, sans)
+	if err != nil {
+		t.Fatalf("Dev() error = %v", err)
+	}
+	cfg.Peers = peers
+	cfg.ReloadInterval = 30 * time.Second
+	files, err := Load(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return files
+}
+
+// handshake runs both sides of a TLS handshake over a loopback connection and
+// returns the state the client saw.
+func handshake(t *testing.T, server, client credentials.TransportCredentials) (credentials.TLSInfo, error) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer lis.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		raw, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		conn, _, err := server.ServerHandshake(raw)
+		if err != nil {
+			raw.Close()
+			serverErr <- err
+			return
+		}
+		// The client only learns that its certificate was rejected when it
+		// reads, since TLS 1.3 servers verify it after the client finishes.
+		_, err = conn.Write([]byte{0})
+		conn.Close()
+		serverErr <- err
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	raw, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	conn, info, err := client.ClientHandshake(ctx, "customer-service:9090", raw)
+	if err != nil {
+		raw.Close()
+		<-serverErr
+		return credentials.TLSInfo{}, err
+	}
+	defer conn.Close()
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		<-serverErr
+		return credentials.TLSInfo{}, err
+	}
+	if err := <-serverErr; err != nil {
+		return credentials.TLSInfo{}, err
+	}
+
+	tlsInfo, ok := info.(credentials.TLSInfo)
+	if !ok {
+		return credentials.TLSInfo{}, errors.New("client auth info is not TLS")
+	}
+	return tlsInfo, nil
+}
@@ -0,0 +1,252 @@
+// Package ratelimit throttles callers with token buckets. Each route has a
+// policy saying how fast its bucket refills, how many requests it holds and
+// whether callers are told apart by client, tenant or IP address.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRoute names the policy of routes without one of their own.
+const DefaultRoute = "default"
+
+// AuthRoute names the policy applied by client address before a request is
+// authenticated.
+const AuthRoute = "auth"
+
+// KeyBy chooses what a bucket belongs to.
+type KeyBy string
+
+const (
+	// ByClient gives every API key and token subject a bucket.
+	ByClient KeyBy = "client"
+	// ByTenant shares a bucket between all clients of a tenant.
+	ByTenant KeyBy = "tenant"
+	// ByIP gives every client address a bucket.
+	ByIP KeyBy = "ip"
+)
+
+var ErrInvalidPolicy = errors.New("invalid rate limit policy")
+
+// Policy is a token bucket: it holds up to Burst requests and refills at
+// Rate requests per second.
+type Policy struct {
+	Rate  float64
+	Burst int
+	By    KeyBy
+}
+
+// Window is how long an empty bucket takes to fill up.
+func (p Policy) Window() time.Duration {
+	return time.Duration(float64(p.Burst) / p.Rate * float64(time.Second))
+}
+
+// Decision is the outcome of taking a request from a bucket.
+type Decision struct {
+	Allowed bool
+	// Limit is the capacity of the bucket and Remaining what is left of it.
+	Limit     int
+	Remaining int
+	// Reset is when the bucket is full again; RetryAfter, for a rejected
+	// request, when the next one fits.
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// ParsePolicies reads policies written as
+//
+//	POST /api/v1/shipments=10/s,burst=20,by=client;default=100/m,by=ip
+//
+// Entries are separated by ";" and name a route pattern or "default". The
+// rate is a number of requests per s, m or h; burst defaults to one second's
+// worth of requests, or one, and by to client.
+func ParsePolicies(value string) (map[string]Policy, error) {
+	policies := make(map[string]Policy)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, spec, ok := strings.Cut(entry, "=")
+		route = strings.TrimSpace(route)
+		if !ok || route == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPolicy, entry)
+		}
+		policy, err := parsePolicy(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, entry)
+		}
+		policies[route] = policy
+	}
+	return policies, nil
+}
+
+func parsePolicy(spec string) (Policy, error) {
+	fields := strings.Split(spec, ",")
+	count, period, ok := strings.Cut(strings.TrimSpace(fields[0]), "/")
+	requests, err := strconv.ParseFloat(count, 64)
+	if !ok || err != nil || requests <= 0 || math.IsInf(requests, 0) {
+		return Policy{}, ErrInvalidPolicy
+	}
+
+	policy := Policy{By: ByClient}
+	switch period {
+	case "s":
+		policy.Rate = requests
+	case "m":
+		policy.Rate = requests / 60
+	case "h":
+		policy.Rate = requests / 3600
+	default:
+		return Policy{}, ErrInvalidPolicy
+	}
+	policy.Burst = max(int(math.Ceil(policy.Rate)), 1)
+
+	for _, field := range fields[1:] {
+		name, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		switch name {
+		case "burst":
+			if policy.Burst, err = strconv.Atoi(value); err != nil || policy.Burst < 1 {
+				return Policy{}, ErrInvalidPolicy
+			}
+		case "by":
+			policy.By = KeyBy(value)
+			if policy.By != ByClient && policy.By != ByTenant && policy.By != ByIP {
+				return Policy{}, ErrInvalidPolicy
+			}
+		default:
+			return Policy{}, ErrInvalidPolicy
+		}
+	}
+	return policy, nil
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	policy  Policy
+}
+
+// fill adds the tokens refilled since the last update.
+func (b *bucket) fill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = min(b.tokens+elapsed*b.policy.Rate, float64(b.policy.Burst))
+	b.updated = now
+}
+
+// sweepInterval is how often buckets that have filled up again, and so carry
+// no state, are dropped.
+const sweepInterval = time.Minute
+
+type Config struct {
+	// Policies by route pattern, see ParsePolicies.
+	Policies map[string]Policy
+	// TrustForwardedFor takes the client address from the last
+	// X-Forwarded-For entry, as appended by a proxy in front of the service.
+	TrustForwardedFor bool
+}
+
+// Limiter keeps the buckets of one process. Replicas each have their own, so
+// the effective limit grows with the number of replicas.
+type Limiter struct {
+	policies          map[string]Policy
+	trustForwardedFor bool
+	now               func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func New(cfg Config) *Limiter {
+	return &Limiter{
+		policies:          cfg.Policies,
+		trustForwardedFor: cfg.TrustForwardedFor,
+		now:               time.Now,
+		buckets:           make(map[string]*bucket),
+		lastSweep:         time.Now(),
+	}
+}
+
+// ClientIP is the address ByIP buckets of r are keyed by.
+func (l *Limiter) ClientIP(r *http.Request) string {
+	if l.trustForwardedFor {
+		if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			hops := strings.Split(values[len(values)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Policy returns the policy of route, falling back to the default one.
+func (l *Limiter) Policy(route string) (Policy, bool) {
+	if policy, ok := l.policies[route]; ok {
+		return policy, true
+	}
+	policy, ok := l.policies[DefaultRoute]
+	return policy, ok
+}
+
+// Allow takes a request of key from the bucket of route.
+func (l *Limiter) Allow(route, key string) Decision {
+	policy, ok := l.Policy(route)
+	if !ok {
+		return Decision{Allowed: true}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	id := route + "\x00" + key
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{tokens: float64(policy.Burst), updated: now, policy: policy}
+		l.buckets[id] = b
+	}
+	b.fill(now)
+
+	decision := Decision{Limit: policy.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = seconds((1 - b.tokens) / policy.Rate)
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = seconds((float64(policy.Burst) - b.tokens) / policy.Rate)
+	return decision
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for id, b := range l.buckets {
+		if b.fill(now); b.tokens >= float64(b.policy.Burst) {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParsePolicies(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]Policy
+		err   error
+	}{
+		{name: "empty", value: "", want: map[string]Policy{}},
+		{
+			name:  "defaults",
+			value: "default=10/s",
+			want:  map[string]Policy{"default": {Rate: 10, Burst: 10, By: ByClient}},
+		},
+		{
+			name:  "routes",
+			value: "POST /api/v1/shipments=120/m,burst=5,by=tenant; GET /api/v1/audit=1/h,by=ip",
+			want: map[string]Policy{
+				"POST /api/v1/shipments": {Rate: 2, Burst: 5, By: ByTenant},
+				"GET /api/v1/audit":      {Rate: 1.0 / 3600, Burst: 1, By: ByIP},
+			},
+		},
+		{name: "missing rate", value: "default", err: ErrInvalidPolicy},
+		{name: "unknown period", value: "default=10/d", err: ErrInvalidPolicy},
+		{name: "zero rate", value: "default=0/s", err: ErrInvalidPolicy},
+		{name: "zero burst", value: "default=1/s,burst=0", err: ErrInvalidPolicy},
+		{name: "unknown key", value: "default=1/s,by=country", err: ErrInvalidPolicy},
+		{name: "unknown option", value: "default=1/s,window=5", err: ErrInvalidPolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicies(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ParsePolicies() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParsePolicies() = %v, want %v", got, tt.want)
+			}
+			for route, policy := range tt.want {
+				if got[route] != policy {
+					t.Fatalf("ParsePolicies()[%q] = %+v, want %+v", route, got[route], policy)
+				}
+			}
+		})
+	}
+}
+
+func TestAllow(t *testing.T) {
+	limiter := New(Config{Policies: map[string]Policy{
+		"POST /api/v1/shipments": {Rate: 1, Burst: 2, By: ByClient},
+	}})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i, want := range []Decision{
+		{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second},
+		{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second},
+		{Allowed: false, Limit: 2, Remaining: 0, Reset: 2 * time.Second, RetryAfter: time.Second},
+	} {
+		if got := limiter.Allow("POST /api/v1/shipments", "client:a"); got != want {
+			t.Fatalf("Allow() #%d = %+v, want %+v", i+1, got, want)
+		}
+	}
+
+	if got := limiter.Allow("POST /api/v1/shipments", "client:b"); !got.Allowed {
+		t.Fatalf("Allow() for another key = %+v, want allowed", got)
+	}
+	if got := limiter.Allow("GET /api/v1/audit", "client:a"); !got.Allowed {
+		t.Fatalf("Allow() without a policy = %+v, want allowed", got)
+	}
+
+	now = now.Add(time.Second)
+	if got := limiter.Allow("POST /api/v1/shipments", "client:a"); !got.Allowed {
+		t.Fatalf("Allow() after refill = %+v, want allowed", got)
+	}
+}
+
+func TestSweepDropsFullBuckets(t *testing.T) {
+	limiter := New(Config{Policies: map[string]Policy{DefaultRoute: {Rate: 1, Burst: 1}}})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("GET /api/v1/audit", "client:a")
+	now = now.Add(sweepInterval)
+	limiter.Allow("GET /api/v1/audit", "client:b")
+
+	if len(limiter.buckets) != 1 {
+		t.Fatalf("buckets = %d after sweep, want 1", len(limiter.buckets))
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		trust     bool
+		forwarded string
+		want      string
+	}{
+		{name: "remote address", want: "192.0.2.1"},
+		{name: "forwarded ignored", forwarded: "198.51.100.7", want: "192.0.2.1"},
+		{name: "last forwarded hop", trust: true, forwarded: "203.0.113.9, 198.51.100.7", want: "198.51.100.7"},
+		{name: "trusted without header", trust: true, want: "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/", nil)
+			if tt.forwarded != "" {
+				request.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := New(Config{TrustForwardedFor: tt.trust}).ClientIP(request); got != tt.want {
+				t.Fatalf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
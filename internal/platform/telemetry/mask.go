@@ -0,0 +1,12 @@
+package telemetry
+
+import "strings"
+
+// MaskIDN hides all but the last four characters of an IDN so that log lines
+// and spans can still be correlated by support staff without exposing it.
+func MaskIDN(idn string) string {
+	if len(idn) <= 4 {
+		return strings.Repeat("*", len(idn))
+	}
+	return strings.Repeat("*", len(idn)-4) + idn[len(idn)-4:]
+}
@@ -0,0 +1,105 @@
+package telemetry
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// InitMeterProvider installs the global meter provider and returns it with
+// the handler serving its metrics to Prometheus. The handler also exposes Go
+// runtime and process metrics. Instruments created before the call report to
+// the new provider as well.
+func InitMeterProvider(ctx context.Context, serviceName string) (*sdkmetric.MeterProvider, http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(attribute.String("service.name", serviceName)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(exporter),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(provider)
+
+	return provider, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
+
+// ObserveDBStats reports the connection pool of db, as seen by sql.DB.Stats,
+// on every collection.
+func ObserveDBStats(db *sql.DB, name string) error {
+	meter := otel.Meter("database/sql")
+	pool := metric.WithAttributes(attribute.String("db.client.connection.pool.name", name))
+
+	connections, err := meter.Int64ObservableUpDownCounter(
+		"db.client.connection.count",
+		metric.WithDescription("Open connections by state: idle or used"),
+	)
+	if err != nil {
+		return err
+	}
+	maxOpen, err := meter.Int64ObservableUpDownCounter(
+		"db.client.connection.max",
+		metric.WithDescription("Maximum open connections, 0 for unlimited"),
+	)
+	if err != nil {
+		return err
+	}
+	waits, err := meter.Int64ObservableCounter(
+		"db.client.connection.waits",
+		metric.WithDescription("Connections waited for because the pool was exhausted"),
+	)
+	if err != nil {
+		return err
+	}
+	waitTime, err := meter.Float64ObservableCounter(
+		"db.client.connection.wait_time",
+		metric.WithDescription("Total time spent waiting for a connection"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+	closed, err := meter.Int64ObservableCounter(
+		"db.client.connection.closed",
+		metric.WithDescription("Connections closed by reason: max_idle, max_idle_time or max_lifetime"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		stats := db.Stats()
+		o.ObserveInt64(connections, int64(stats.Idle), pool, metric.WithAttributes(attribute.String("db.client.connection.state", "idle")))
+		o.ObserveInt64(connections, int64(stats.InUse), pool, metric.WithAttributes(attribute.String("db.client.connection.state", "used")))
+		o.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections), pool)
+		o.ObserveInt64(waits, stats.WaitCount, pool)
+		o.ObserveFloat64(waitTime, stats.WaitDuration.Seconds(), pool)
+		o.ObserveInt64(closed, stats.MaxIdleClosed, pool, metric.WithAttributes(attribute.String("reason", "max_idle")))
+		o.ObserveInt64(closed, stats.MaxIdleTimeClosed, pool, metric.WithAttributes(attribute.String("reason", "max_idle_time")))
+		o.ObserveInt64(closed, stats.MaxLifetimeClosed, pool, metric.WithAttributes(attribute.String("reason", "max_lifetime")))
+		return nil
+	}, connections, maxOpen, waits, waitTime, closed)
+	return err
+}
@@ -0,0 +1,46 @@
+package telemetry
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestMetricsHandlerServesDBStats(t *testing.T) {
+	provider, handler, err := InitMeterProvider(context.Background(), "test-service")
+	if err != nil {
+		t.Fatalf("InitMeterProvider() error = %v", err)
+	}
+	defer provider.Shutdown(context.Background())
+
+	// Opening does not connect, the pool is just empty.
+	db, err := sql.Open("pgx", "postgres://127.0.0.1:1/none")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+	if err := ObserveDBStats(db, "test"); err != nil {
+		t.Fatalf("ObserveDBStats() error = %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+
+	for _, want := range []string{
+		`db_client_connection_max{db_client_connection_pool_name="test"`,
+		`db_client_connection_count{db_client_connection_pool_name="test",db_client_connection_state="idle"`,
+		`go_goroutines `,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("metrics do not contain %s:\n%s", want, body)
+		}
+	}
+}
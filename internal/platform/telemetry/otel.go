@@ -13,7 +13,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-func InitProvider(ctx context.Context, serviceName, endpoint string) (*sdktrace.TracerProvider, error) {
+func InitProvider(ctx context.Context, serviceName, endpoint string, redactor *Redactor) (*sdktrace.TracerProvider, error) {
 	client := otlptracegrpc.NewClient(otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
 	exporter, err := otlptrace.New(ctx, client)
 	if err != nil {
@@ -26,7 +26,7 @@ func InitProvider(ctx context.Context, serviceName, endpoint string) (*sdktrace.
 	}
 
 	provider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSpanProcessor(NewRedactingSpanProcessor(sdktrace.NewBatchSpanProcessor(exporter), redactor)),
 		sdktrace.WithResource(res),
 	)
 
@@ -0,0 +1,241 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RedactionConfig lists attribute keys whose values are always masked and
+// value patterns that are masked wherever they appear.
+type RedactionConfig struct {
+	Keys     []string
+	Patterns []*regexp.Regexp
+}
+
+func DefaultRedactionConfig() RedactionConfig {
+	return RedactionConfig{
+		Keys: []string{"idn", "phone", "email", "authorization", "password", "secret", "api_key", "token"},
+		Patterns: []*regexp.Regexp{
+			// Kazakhstan IIN/BIN.
+			regexp.MustCompile(`\b\d{12}\b`),
+			// +7/8 numbers with optional separators, then generic E.164.
+			regexp.MustCompile(`(?:\+7|\b8)[ \-]?\(?\d{3}\)?[ \-]?\d{3}[ \-]?\d{2}[ \-]?\d{2}\b`),
+			regexp.MustCompile(`\+\d{10,14}\b`),
+			regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		},
+	}
+}
+
+// Redactor masks sensitive values before they reach logs or trace exporters.
+type Redactor struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+func NewRedactor(cfg RedactionConfig) *Redactor {
+	keys := make(map[string]struct{}, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		keys[strings.ToLower(strings.TrimSpace(key))] = struct{}{}
+	}
+	return &Redactor{keys: keys, patterns: cfg.Patterns}
+}
+
+// String returns value masked according to key and the configured patterns.
+// Keys match case-insensitively on the full key or its last dot- or
+// underscore-separated segment, so "idn" also covers "customer.idn".
+func (r *Redactor) String(key, value string) string {
+	if r.sensitiveKey(key) {
+		return MaskIDN(value)
+	}
+	for _, pattern := range r.patterns {
+		value = maskMatches(pattern, value)
+	}
+	return value
+}
+
+func (r *Redactor) sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := r.keys[key]; ok {
+		return true
+	}
+	if i := strings.LastIndexAny(key, "._"); i >= 0 {
+		_, ok := r.keys[key[i+1:]]
+		return ok
+	}
+	return false
+}
+
+// maskMatches masks every match of pattern that is not glued to a hyphen,
+// which keeps all-digit UUID groups and similar identifiers intact.
+func maskMatches(pattern *regexp.Regexp, value string) string {
+	matches := pattern.FindAllStringIndex(value, -1)
+	if matches == nil {
+		return value
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if (m[0] > 0 && value[m[0]-1] == '-') || (m[1] < len(value) && value[m[1]] == '-') {
+			continue
+		}
+		b.WriteString(value[last:m[0]])
+		b.WriteString(MaskIDN(value[m[0]:m[1]]))
+		last = m[1]
+	}
+	b.WriteString(value[last:])
+	return b.String()
+}
+
+func (r *Redactor) attr(a slog.Attr) slog.Attr {
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.String(a.Key, value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		attrs := make([]any, len(group))
+		for i, attr := range group {
+			attrs[i] = r.attr(attr)
+		}
+		return slog.Group(a.Key, attrs...)
+	case slog.KindAny:
+		text := fmt.Sprint(value.Any())
+		if redacted := r.String(a.Key, text); redacted != text {
+			return slog.String(a.Key, redacted)
+		}
+		return slog.Attr{Key: a.Key, Value: value}
+	default:
+		text := value.String()
+		if redacted := r.String(a.Key, text); redacted != text {
+			return slog.String(a.Key, redacted)
+		}
+		return slog.Attr{Key: a.Key, Value: value}
+	}
+}
+
+func (r *Redactor) keyValue(kv attribute.KeyValue) attribute.KeyValue {
+	key := string(kv.Key)
+	switch kv.Value.Type() {
+	case attribute.STRING:
+		return attribute.String(key, r.String(key, kv.Value.AsString()))
+	case attribute.STRINGSLICE:
+		values := kv.Value.AsStringSlice()
+		redacted := make([]string, len(values))
+		for i, v := range values {
+			redacted[i] = r.String(key, v)
+		}
+		return attribute.StringSlice(key, redacted)
+	default:
+		text := kv.Value.Emit()
+		if redacted := r.String(key, text); redacted != text {
+			return attribute.String(key, redacted)
+		}
+		return kv
+	}
+}
+
+func (r *Redactor) keyValues(kvs []attribute.KeyValue) []attribute.KeyValue {
+	if len(kvs) == 0 {
+		return kvs
+	}
+	redacted := make([]attribute.KeyValue, len(kvs))
+	for i, kv := range kvs {
+		redacted[i] = r.keyValue(kv)
+	}
+	return redacted
+}
+
+// RedactingHandler is a slog.Handler that masks sensitive attributes and
+// message text before passing records to the wrapped handler.
+type RedactingHandler struct {
+	next     slog.Handler
+	redactor *Redactor
+}
+
+func NewRedactingHandler(next slog.Handler, redactor *Redactor) *RedactingHandler {
+	return &RedactingHandler{next: next, redactor: redactor}
+}
+
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redactor.String("", record.Message), record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactor.attr(a)
+	}
+	return &RedactingHandler{next: h.next.WithAttrs(redacted), redactor: h.redactor}
+}
+
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{next: h.next.WithGroup(name), redactor: h.redactor}
+}
+
+// RedactingSpanProcessor masks span attributes, event attributes and status
+// descriptions of finished spans before handing them to the next processor.
+type RedactingSpanProcessor struct {
+	next     sdktrace.SpanProcessor
+	redactor *Redactor
+}
+
+func NewRedactingSpanProcessor(next sdktrace.SpanProcessor, redactor *Redactor) *RedactingSpanProcessor {
+	return &RedactingSpanProcessor{next: next, redactor: redactor}
+}
+
+func (p *RedactingSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *RedactingSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.next.OnEnd(redactedSpan{ReadOnlySpan: s, redactor: p.redactor})
+}
+
+func (p *RedactingSpanProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *RedactingSpanProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	redactor *Redactor
+}
+
+func (s redactedSpan) Attributes() []attribute.KeyValue {
+	return s.redactor.keyValues(s.ReadOnlySpan.Attributes())
+}
+
+func (s redactedSpan) Events() []sdktrace.Event {
+	events := s.ReadOnlySpan.Events()
+	redacted := make([]sdktrace.Event, len(events))
+	for i, event := range events {
+		event.Attributes = s.redactor.keyValues(event.Attributes)
+		redacted[i] = event
+	}
+	return redacted
+}
+
+func (s redactedSpan) Status() sdktrace.Status {
+	status := s.ReadOnlySpan.Status()
+	status.Description = s.redactor.String("", status.Description)
+	return status
+}
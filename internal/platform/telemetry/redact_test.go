@@ -0,0 +1,102 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const rawIDN = "990101123456"
+
+func TestRedactorString(t *testing.T) {
+	redactor := NewRedactor(DefaultRedactionConfig())
+
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		want  string
+	}{
+		{name: "sensitive key", key: "idn", value: rawIDN, want: "********3456"},
+		{name: "dotted key", key: "customer.idn", value: rawIDN, want: "********3456"},
+		{name: "underscored key", key: "customer_idn", value: rawIDN, want: "********3456"},
+		{name: "idn in text", key: "error", value: "customer " + rawIDN + " not found", want: "customer ********3456 not found"},
+		{name: "phone", key: "note", value: "call +7 701 123 45 67", want: "call ************5 67"},
+		{name: "email", key: "note", value: "mail john@example.kz", want: "mail ***********e.kz"},
+		{name: "uuid untouched", key: "shipment_id", value: "11111111-2222-3333-4444-555555555555", want: "11111111-2222-3333-4444-555555555555"},
+		{name: "plain value", key: "route", value: "ALMATY->ASTANA", want: "ALMATY->ASTANA"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := redactor.String(tc.key, tc.value); got != tc.want {
+				t.Fatalf("String(%q, %q) = %q, want %q", tc.key, tc.value, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRedactingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewRedactingHandler(slog.NewJSONHandler(&buf, nil), NewRedactor(DefaultRedactionConfig())))
+
+	logger.With(slog.String("idn", rawIDN)).WithGroup("request").Info(
+		"upsert "+rawIDN,
+		slog.String("idn", rawIDN),
+		slog.Int64("customer_idn", 990101123456),
+		slog.Any("error", errors.New("duplicate idn "+rawIDN)),
+		slog.Group("customer", slog.String("note", "idn="+rawIDN)),
+	)
+
+	if strings.Contains(buf.String(), rawIDN) {
+		t.Fatalf("log output contains raw idn: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "********3456") {
+		t.Fatalf("log output has no masked idn: %s", buf.String())
+	}
+}
+
+func TestRedactingSpanProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewRedactingSpanProcessor(recorder, NewRedactor(DefaultRedactionConfig()))),
+	)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "upsert")
+	span.SetAttributes(attribute.String("customer.idn", rawIDN), attribute.String("db.statement", "idn = "+rawIDN))
+	span.AddEvent("lookup", trace.WithAttributes(attribute.String("note", rawIDN)))
+	span.RecordError(errors.New("bad idn " + rawIDN))
+	span.SetStatus(codes.Error, "bad idn "+rawIDN)
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	ended := spans[0]
+
+	for _, kv := range ended.Attributes() {
+		if strings.Contains(kv.Value.Emit(), rawIDN) {
+			t.Fatalf("span attribute %s contains raw idn", kv.Key)
+		}
+	}
+	for _, event := range ended.Events() {
+		for _, kv := range event.Attributes {
+			if strings.Contains(kv.Value.Emit(), rawIDN) {
+				t.Fatalf("event %s attribute %s contains raw idn", event.Name, kv.Key)
+			}
+		}
+	}
+	if strings.Contains(ended.Status().Description, rawIDN) {
+		t.Fatalf("span status contains raw idn: %s", ended.Status().Description)
+	}
+}
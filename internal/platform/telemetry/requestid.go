@@ -0,0 +1,78 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDHeader carries the request ID over HTTP; gRPC calls use its
+// lower-case form as metadata key.
+const RequestIDHeader = "X-Request-Id"
+
+const requestIDMetadataKey = "x-request-id"
+
+// requestIDPattern bounds IDs supplied by callers, which end up in logs and
+// the audit log.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type requestIDKey struct{}
+
+func NewRequestIDContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the ID of the request ctx belongs to, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDOrNew keeps a well-formed ID chosen by the caller, so one ID
+// follows a request across services, and makes one up otherwise.
+func requestIDOrNew(id string) string {
+	if requestIDPattern.MatchString(id) {
+		return id
+	}
+	return uuid.NewString()
+}
+
+// RequestIDMiddleware gives every request an ID and echoes it in the
+// response. The request header is rewritten too, so handlers proxying to gRPC
+// pass the same ID on.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestIDOrNew(r.Header.Get(RequestIDHeader))
+		r.Header.Set(RequestIDHeader, id)
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(NewRequestIDContext(r.Context(), id)))
+	})
+}
+
+// UnaryServerRequestID gives every gRPC call an ID, taken from the x-request-id
+// metadata when present, and returns it as a response header.
+func UnaryServerRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var requested string
+		if values := metadata.ValueFromIncomingContext(ctx, requestIDMetadataKey); len(values) > 0 {
+			requested = values[0]
+		}
+		id := requestIDOrNew(requested)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, id))
+		return handler(NewRequestIDContext(ctx, id), req)
+	}
+}
+
+// UnaryClientRequestID passes the request ID of ctx on to the called service.
+func UnaryClientRequestID() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id := RequestID(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, requestIDMetadataKey, id)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,136 @@
+// Package tenant carries the brand a request acts for and confines database
+// access to it.
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Default owns the data of single-brand deployments and everything stored
+	// before tenants were introduced.
+	Default = "default"
+	// All scopes background work that spans tenants, such as the outbox relay.
+	// It is never accepted for writes.
+	All = "*"
+	// MetadataKey carries the tenant on calls between the services.
+	MetadataKey = "x-tenant-id"
+	// dbRole is the database role row-level security applies to. Scoped
+	// transactions switch to it so that the policies hold even when the
+	// service connects as the table owner or a superuser.
+	dbRole = "tenant_scoped"
+)
+
+var (
+	ErrMissing  = errors.New("no tenant in context")
+	ErrInvalid  = errors.New("invalid tenant id")
+	ErrMismatch = errors.New("tenant does not match the caller")
+)
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+type tenantKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant scope of ctx, which may be All.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Scope returns the tenant scope of ctx for queries that may span tenants:
+// either a tenant or All.
+func Scope(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrMissing
+	}
+	return id, nil
+}
+
+// ID returns the single tenant ctx acts for. Contexts scoped to All have no
+// such tenant and get ErrMissing, so cross-tenant workers cannot write rows
+// without picking a tenant first.
+func ID(ctx context.Context) (string, error) {
+	id, ok := FromContext(ctx)
+	if !ok || id == All {
+		return "", ErrMissing
+	}
+	return id, nil
+}
+
+// Resolve picks the tenant of a call from the caller's own tenant and the one
+// it requested. A caller bound to a tenant may only act for that tenant;
+// callers without one, i.e. platform service accounts and trusted internal
+// calls, act for the requested tenant or Default. All is only honoured when
+// allowAll is set.
+func Resolve(callerTenant, requested string, allowAll bool) (string, error) {
+	if callerTenant != "" {
+		if requested != "" && requested != callerTenant {
+			return "", ErrMismatch
+		}
+		return callerTenant, nil
+	}
+	switch {
+	case requested == "":
+		return Default, nil
+	case requested == All && allowAll:
+		return All, nil
+	case !Valid(requested):
+		return "", ErrInvalid
+	}
+	return requested, nil
+}
+
+// FromIncomingContext returns the tenant requested in the metadata of an
+// incoming gRPC call, or "".
+func FromIncomingContext(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, MetadataKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// AppendToOutgoingContext passes the tenant of ctx on to the next service.
+func AppendToOutgoingContext(ctx context.Context) context.Context {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, id)
+}
+
+// BeginTx starts a transaction that row-level security confines to the
+// tenant scope of ctx. Queries should still filter by tenant themselves; the
+// policies catch the ones that forget. Read-only callers may simply roll the
+// transaction back.
+func BeginTx(ctx context.Context, db *sql.DB) (*sql.Tx, error) {
+	id, err := Scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// set_config with is_local = true is SET LOCAL with bind parameters; both
+	// settings end with the transaction and never leak to pooled connections.
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('role', $1, true), set_config('app.tenant_id', $2, true)`, dbRole, id); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return tx, nil
+}
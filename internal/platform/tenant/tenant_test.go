@@ -0,0 +1,52 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		caller    string
+		requested string
+		allowAll  bool
+		want      string
+		wantErr   error
+	}{
+		{name: "caller tenant", caller: "brand-a", want: "brand-a"},
+		{name: "caller repeats own tenant", caller: "brand-a", requested: "brand-a", want: "brand-a"},
+		{name: "caller asks for another tenant", caller: "brand-a", requested: "brand-b", wantErr: ErrMismatch},
+		{name: "caller asks for all", caller: "brand-a", requested: All, allowAll: true, wantErr: ErrMismatch},
+		{name: "service defaults", want: Default},
+		{name: "service picks tenant", requested: "brand-b", want: "brand-b"},
+		{name: "service asks for all", requested: All, allowAll: true, want: All},
+		{name: "all not allowed", requested: All, wantErr: ErrInvalid},
+		{name: "invalid tenant", requested: "Brand A", wantErr: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.caller, tt.requested, tt.allowAll)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDRejectsAll(t *testing.T) {
+	if _, err := ID(context.Background()); !errors.Is(err, ErrMissing) {
+		t.Fatalf("ID() error = %v, want %v", err, ErrMissing)
+	}
+	if _, err := ID(NewContext(context.Background(), All)); !errors.Is(err, ErrMissing) {
+		t.Fatalf("ID() error = %v, want %v", err, ErrMissing)
+	}
+	if got, err := ID(NewContext(context.Background(), "brand-a")); err != nil || got != "brand-a" {
+		t.Fatalf("ID() = %q, %v, want brand-a", got, err)
+	}
+}
@@ -0,0 +1,159 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	domain "shipment-customer-service/internal/domain/apikey"
+	"shipment-customer-service/internal/platform/auth"
+)
+
+const (
+	keyPrefix    = "shk_"
+	prefixLength = len(keyPrefix) + 8
+	// touchInterval bounds how often last_used_at is written for a busy key.
+	touchInterval      = time.Minute
+	minBootstrapKeyLen = 32
+)
+
+type Repository interface {
+	CreateAPIKey(ctx context.Context, name, prefix string, hash []byte, scopes []auth.Scope) (domain.Key, error)
+	ListAPIKeys(ctx context.Context) ([]domain.Key, error)
+	GetAPIKeyByHash(ctx context.Context, hash []byte) (domain.Key, error)
+	RevokeAPIKey(ctx context.Context, id string) (domain.Key, error)
+	TouchAPIKey(ctx context.Context, id string) error
+}
+
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func NewService(repository Repository) *Service {
+	return &Service{repo: repository, now: time.Now}
+}
+
+// Issue creates a key for the tenant in ctx and returns its secret, which is
+// not stored and cannot be shown again.
+func (s *Service) Issue(ctx context.Context, input domain.IssueKeyInput) (domain.Key, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return domain.Key{}, "", domain.ErrInvalidName
+	}
+	if err := validateScopes(input.Scopes); err != nil {
+		return domain.Key{}, "", err
+	}
+
+	secret, err := generateKey()
+	if err != nil {
+		return domain.Key{}, "", err
+	}
+	hash := hashKey(secret)
+
+	key, err := s.repo.CreateAPIKey(ctx, name, secret[:prefixLength], hash[:], input.Scopes)
+	if err != nil {
+		return domain.Key{}, "", err
+	}
+	return key, secret, nil
+}
+
+// Ensure registers secret as a key of the tenant in ctx unless it already
+// exists. It lets a deployment provision a known bootstrap key, e.g. for the
+// first admin. A revoked key stays revoked.
+func (s *Service) Ensure(ctx context.Context, name, secret string, scopes []auth.Scope) (domain.Key, error) {
+	if len(secret) < minBootstrapKeyLen {
+		return domain.Key{}, domain.ErrBootstrapShort
+	}
+	if err := validateScopes(scopes); err != nil {
+		return domain.Key{}, err
+	}
+
+	hash := hashKey(secret)
+	key, err := s.repo.GetAPIKeyByHash(ctx, hash[:])
+	if !errors.Is(err, sql.ErrNoRows) {
+		return key, err
+	}
+	return s.repo.CreateAPIKey(ctx, name, secret[:prefixLength], hash[:], scopes)
+}
+
+func (s *Service) List(ctx context.Context) ([]domain.Key, error) {
+	return s.repo.ListAPIKeys(ctx)
+}
+
+// Revoke disables a key for good. Revoking a revoked key is a no-op.
+func (s *Service) Revoke(ctx context.Context, id string) (domain.Key, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.Key{}, domain.ErrInvalidID
+	}
+
+	key, err := s.repo.RevokeAPIKey(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Key{}, domain.ErrKeyNotFound
+	}
+	return key, err
+}
+
+// Authenticate resolves a presented key to the principal it was issued to.
+func (s *Service) Authenticate(ctx context.Context, secret string) (auth.Principal, error) {
+	if secret == "" {
+		return auth.Principal{}, domain.ErrMissingKey
+	}
+
+	hash := hashKey(secret)
+	key, err := s.repo.GetAPIKeyByHash(ctx, hash[:])
+	if errors.Is(err, sql.ErrNoRows) {
+		return auth.Principal{}, domain.ErrInvalidKey
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if key.RevokedAt != nil {
+		return auth.Principal{}, domain.ErrInvalidKey
+	}
+
+	if key.LastUsedAt == nil || s.now().Sub(*key.LastUsedAt) >= touchInterval {
+		// Usage tracking is best effort and must not fail the request.
+		_ = s.repo.TouchAPIKey(ctx, key.ID)
+	}
+
+	return auth.Principal{
+		ID:       "apikey:" + key.ID,
+		Name:     key.Name,
+		Method:   auth.MethodAPIKey,
+		Scopes:   key.Scopes,
+		TenantID: key.TenantID,
+	}, nil
+}
+
+func validateScopes(scopes []auth.Scope) error {
+	if len(scopes) == 0 {
+		return domain.ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !auth.IsValidScope(scope) {
+			return domain.ErrInvalidScope
+		}
+	}
+	return nil
+}
+
+// hashKey uses a plain SHA-256: keys are 256 random bits, so a slow password
+// hash would add latency to every request without adding protection.
+func hashKey(secret string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(secret))
+}
+
+func generateKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,159 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/apikey"
+	"shipment-customer-service/internal/platform/auth"
+)
+
+type mockRepo struct {
+	keys    map[string]domain.Key
+	touched []string
+}
+
+func newMockRepo() *mockRepo {
+	return &mockRepo{keys: map[string]domain.Key{}}
+}
+
+func (m *mockRepo) CreateAPIKey(ctx context.Context, name, prefix string, hash []byte, scopes []auth.Scope) (domain.Key, error) {
+	key := domain.Key{ID: "k" + string(rune('0'+len(m.keys))), Name: name, Prefix: prefix, Scopes: scopes}
+	m.keys[string(hash)] = key
+	return key, nil
+}
+
+func (m *mockRepo) ListAPIKeys(ctx context.Context) ([]domain.Key, error) {
+	return nil, nil
+}
+
+func (m *mockRepo) GetAPIKeyByHash(ctx context.Context, hash []byte) (domain.Key, error) {
+	key, ok := m.keys[string(hash)]
+	if !ok {
+		return domain.Key{}, sql.ErrNoRows
+	}
+	return key, nil
+}
+
+func (m *mockRepo) RevokeAPIKey(ctx context.Context, id string) (domain.Key, error) {
+	return domain.Key{}, sql.ErrNoRows
+}
+
+func (m *mockRepo) TouchAPIKey(ctx context.Context, id string) error {
+	m.touched = append(m.touched, id)
+	return nil
+}
+
+func TestIssueValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input domain.IssueKeyInput
+		err   error
+	}{
+		{name: "empty name", input: domain.IssueKeyInput{Name: " ", Scopes: []auth.Scope{auth.ScopeAdmin}}, err: domain.ErrInvalidName},
+		{name: "no scopes", input: domain.IssueKeyInput{Name: "ci"}, err: domain.ErrInvalidScope},
+		{name: "unknown scope", input: domain.IssueKeyInput{Name: "ci", Scopes: []auth.Scope{"shipments:delete"}}, err: domain.ErrInvalidScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewService(newMockRepo()).Issue(context.Background(), tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Issue() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestIssueAndAuthenticate(t *testing.T) {
+	repo := newMockRepo()
+	svc := NewService(repo)
+
+	key, secret, err := svc.Issue(context.Background(), domain.IssueKeyInput{Name: "ci", Scopes: []auth.Scope{auth.ScopeShipmentsRead}})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if !strings.HasPrefix(secret, key.Prefix) || len(secret) <= len(key.Prefix) {
+		t.Fatalf("Issue() secret %q does not start with prefix %q", secret, key.Prefix)
+	}
+
+	principal, err := svc.Authenticate(context.Background(), secret)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.ID != "apikey:"+key.ID || !principal.HasScope(auth.ScopeShipmentsRead) || principal.HasScope(auth.ScopeShipmentsWrite) {
+		t.Fatalf("Authenticate() = %+v", principal)
+	}
+	if len(repo.touched) != 1 {
+		t.Fatalf("Authenticate() touched %d keys, want 1", len(repo.touched))
+	}
+}
+
+func TestAuthenticateRejects(t *testing.T) {
+	repo := newMockRepo()
+	svc := NewService(repo)
+	revokedAt := time.Now()
+	repo.keys[string(hashBytes("shk_revoked"))] = domain.Key{ID: "k1", RevokedAt: &revokedAt}
+
+	tests := []struct {
+		name   string
+		secret string
+		err    error
+	}{
+		{name: "missing", secret: "", err: domain.ErrMissingKey},
+		{name: "unknown", secret: "shk_unknown", err: domain.ErrInvalidKey},
+		{name: "revoked", secret: "shk_revoked", err: domain.ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Authenticate(context.Background(), tt.secret); !errors.Is(err, tt.err) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateThrottlesLastUsed(t *testing.T) {
+	repo := newMockRepo()
+	svc := NewService(repo)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	lastUsed := now.Add(-10 * time.Second)
+	repo.keys[string(hashBytes("shk_busy"))] = domain.Key{ID: "k1", LastUsedAt: &lastUsed, Scopes: []auth.Scope{auth.ScopeAdmin}}
+
+	if _, err := svc.Authenticate(context.Background(), "shk_busy"); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if len(repo.touched) != 0 {
+		t.Fatalf("Authenticate() touched a key used %v ago", now.Sub(lastUsed))
+	}
+}
+
+func TestEnsureIsIdempotent(t *testing.T) {
+	repo := newMockRepo()
+	svc := NewService(repo)
+	secret := strings.Repeat("x", minBootstrapKeyLen)
+
+	for range 2 {
+		if _, err := svc.Ensure(context.Background(), "bootstrap", secret, []auth.Scope{auth.ScopeAdmin}); err != nil {
+			t.Fatalf("Ensure() error = %v", err)
+		}
+	}
+	if len(repo.keys) != 1 {
+		t.Fatalf("Ensure() created %d keys, want 1", len(repo.keys))
+	}
+	if _, err := svc.Ensure(context.Background(), "bootstrap", "short", []auth.Scope{auth.ScopeAdmin}); !errors.Is(err, domain.ErrBootstrapShort) {
+		t.Fatalf("Ensure() error = %v, want %v", err, domain.ErrBootstrapShort)
+	}
+}
+
+func hashBytes(secret string) []byte {
+	hash := hashKey(secret)
+	return hash[:]
+}
@@ -0,0 +1,46 @@
+// Package auditlog serves the audit log of a tenant for compliance reviews.
+package auditlog
+
+import (
+	"context"
+
+	"shipment-customer-service/internal/platform/audit"
+)
+
+type Repository interface {
+	ListAuditEntries(ctx context.Context, filter audit.Filter) ([]audit.Entry, error)
+	VerifyAuditChain(ctx context.Context, filter audit.VerifyFilter) (audit.VerifyResult, error)
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repository Repository) *Service {
+	return &Service{repo: repository}
+}
+
+// List returns entries of the tenant in ctx matching filter, oldest first.
+func (s *Service) List(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	if filter.AfterSeq < 0 || filter.Limit < 0 {
+		return nil, audit.ErrInvalidFilter
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, audit.ErrInvalidFilter
+	}
+	filter.Limit = filter.PageSize()
+
+	return s.repo.ListAuditEntries(ctx, filter)
+}
+
+// Verify recomputes a page of the hash chain of the tenant in ctx, following
+// the checkpoint in filter. Long chains are verified page by page, each
+// resuming from the last entry of the one before.
+func (s *Service) Verify(ctx context.Context, filter audit.VerifyFilter) (audit.VerifyResult, error) {
+	if filter.After.Seq < 0 || filter.Limit < 0 || (filter.After.Seq == 0) != (filter.After.Hash == "") {
+		return audit.VerifyResult{}, audit.ErrInvalidFilter
+	}
+	filter.Limit = filter.PageSize()
+
+	return s.repo.VerifyAuditChain(ctx, filter)
+}
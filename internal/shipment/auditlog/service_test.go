@@ -0,0 +1,86 @@
+package auditlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"shipment-customer-service/internal/platform/audit"
+)
+
+type mockRepo struct {
+	filter       audit.Filter
+	verifyFilter audit.VerifyFilter
+}
+
+func (m *mockRepo) ListAuditEntries(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	m.filter = filter
+	return nil, nil
+}
+
+func (m *mockRepo) VerifyAuditChain(ctx context.Context, filter audit.VerifyFilter) (audit.VerifyResult, error) {
+	m.verifyFilter = filter
+	return audit.VerifyResult{Valid: true}, nil
+}
+
+func TestList(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		filter    audit.Filter
+		wantLimit int
+		err       error
+	}{
+		{name: "default limit", filter: audit.Filter{}, wantLimit: audit.DefaultListLimit},
+		{name: "limit clamped", filter: audit.Filter{Limit: 5000}, wantLimit: audit.MaxListLimit},
+		{name: "explicit limit", filter: audit.Filter{Limit: 10, From: now.Add(-time.Hour), To: now}, wantLimit: 10},
+		{name: "negative limit", filter: audit.Filter{Limit: -1}, err: audit.ErrInvalidFilter},
+		{name: "negative after", filter: audit.Filter{AfterSeq: -1}, err: audit.ErrInvalidFilter},
+		{name: "empty range", filter: audit.Filter{From: now, To: now}, err: audit.ErrInvalidFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepo{}
+			_, err := NewService(repo).List(context.Background(), tt.filter)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("List() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && repo.filter.Limit != tt.wantLimit {
+				t.Fatalf("List() limit = %d, want %d", repo.filter.Limit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	checkpoint := audit.Checkpoint{Seq: 42, Hash: "ab12"}
+	tests := []struct {
+		name      string
+		filter    audit.VerifyFilter
+		wantLimit int
+		err       error
+	}{
+		{name: "from the start", filter: audit.VerifyFilter{}, wantLimit: audit.DefaultVerifyLimit},
+		{name: "from a checkpoint", filter: audit.VerifyFilter{After: checkpoint, Limit: 10}, wantLimit: 10},
+		{name: "limit clamped", filter: audit.VerifyFilter{Limit: 50000}, wantLimit: audit.MaxVerifyLimit},
+		{name: "negative limit", filter: audit.VerifyFilter{Limit: -1}, err: audit.ErrInvalidFilter},
+		{name: "negative after", filter: audit.VerifyFilter{After: audit.Checkpoint{Seq: -1, Hash: "ab12"}}, err: audit.ErrInvalidFilter},
+		{name: "after without hash", filter: audit.VerifyFilter{After: audit.Checkpoint{Seq: 42}}, err: audit.ErrInvalidFilter},
+		{name: "hash without after", filter: audit.VerifyFilter{After: audit.Checkpoint{Hash: "ab12"}}, err: audit.ErrInvalidFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepo{}
+			_, err := NewService(repo).Verify(context.Background(), tt.filter)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && repo.verifyFilter.Limit != tt.wantLimit {
+				t.Fatalf("Verify() limit = %d, want %d", repo.verifyFilter.Limit, tt.wantLimit)
+			}
+		})
+	}
+}
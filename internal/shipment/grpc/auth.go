@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	shipmentpb "shipment-customer-service/api/proto/shipment"
+	apikeydomain "shipment-customer-service/internal/domain/apikey"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/platform/tenant"
+	"shipment-customer-service/internal/shipment/apikey"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodScopes is the scope each RPC requires.
+var methodScopes = map[string]auth.Scope{
+	shipmentpb.ShipmentService_CreateShipment_FullMethodName:     auth.ScopeShipmentsWrite,
+	shipmentpb.ShipmentService_GetShipment_FullMethodName:        auth.ScopeShipmentsRead,
+	shipmentpb.ShipmentService_ListShipments_FullMethodName:      auth.ScopeShipmentsRead,
+	shipmentpb.ShipmentService_TransitionShipment_FullMethodName: auth.ScopeShipmentsWrite,
+	shipmentpb.ShipmentService_CancelShipment_FullMethodName:     auth.ScopeShipmentsWrite,
+	shipmentpb.ShipmentService_WatchShipments_FullMethodName:     auth.ScopeShipmentsRead,
+}
+
+// Authenticator checks the API key or bearer token of a call and scopes it to
+// the caller's tenant. Calls without credentials are rejected: without a
+// principal the service policy would grant them every shipment of a tenant
+// of their choosing. The v2 gateway forwards the REST caller's credentials,
+// so the service policy sees the same principal and tenant on both APIs.
+type Authenticator struct {
+	keys   *apikey.Service
+	tokens *auth.Verifier
+	logger *slog.Logger
+}
+
+// NewAuthenticator accepts bearer tokens only if tokens is not nil.
+func NewAuthenticator(keys *apikey.Service, tokens *auth.Verifier, logger *slog.Logger) *Authenticator {
+	return &Authenticator{keys: keys, tokens: tokens, logger: logger}
+}
+
+func (a *Authenticator) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func (a *Authenticator) authorize(ctx context.Context, method string) (context.Context, error) {
+	key := firstValue(ctx, strings.ToLower(auth.APIKeyHeader))
+	token := auth.BearerToken(firstValue(ctx, "authorization"))
+	var (
+		principal auth.Principal
+		err       error
+	)
+	switch {
+	case key == "" && token == "":
+		err = apikeydomain.ErrMissingKey
+	case token != "" && a.tokens != nil:
+		principal, err = a.tokens.Verify(ctx, token)
+	default:
+		principal, err = a.keys.Authenticate(ctx, key)
+	}
+	if errors.Is(err, apikeydomain.ErrMissingKey) || errors.Is(err, apikeydomain.ErrInvalidKey) || errors.Is(err, auth.ErrInvalidToken) {
+		a.logger.Warn(
+			"auth_failed",
+			slog.String("method", method),
+			slog.String("reason", err.Error()),
+			slog.String("trace_id", telemetry.TraceID(ctx)),
+		)
+		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("enduser.id", principal.ID),
+		attribute.String("auth.method", principal.Method),
+	)
+
+	scope, ok := methodScopes[method]
+	if !ok || !principal.HasScope(scope) {
+		a.logger.Warn(
+			"auth_forbidden",
+			slog.String("method", method),
+			slog.String("principal", principal.ID),
+			slog.String("trace_id", telemetry.TraceID(ctx)),
+		)
+		return nil, status.Error(codes.PermissionDenied, "credentials do not grant access to this method")
+	}
+	if principal.TenantID == "" {
+		a.logger.Warn(
+			"auth_no_tenant",
+			slog.String("method", method),
+			slog.String("principal", principal.ID),
+			slog.String("trace_id", telemetry.TraceID(ctx)),
+		)
+		return nil, status.Error(codes.PermissionDenied, "credentials are not bound to a tenant")
+	}
+
+	return a.scopeTenant(auth.NewContext(ctx, principal), method, principal)
+}
+
+func (a *Authenticator) scopeTenant(ctx context.Context, method string, principal auth.Principal) (context.Context, error) {
+	requested := tenant.FromIncomingContext(ctx)
+	id, err := tenant.Resolve(principal.TenantID, requested, false)
+	if err != nil {
+		a.logger.Warn(
+			"tenant_rejected",
+			slog.String("method", method),
+			slog.String("tenant_id", requested),
+			slog.String("reason", err.Error()),
+			slog.String("principal", principal.ID),
+			slog.String("trace_id", telemetry.TraceID(ctx)),
+		)
+		return nil, status.Error(codes.PermissionDenied, "tenant is invalid or not available to the caller")
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("tenant.id", id))
+	return tenant.NewContext(ctx, id), nil
+}
+
+func firstValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"io"
+	"log/slog"
+	"testing"
+
+	shipmentpb "shipment-customer-service/api/proto/shipment"
+	apikeydomain "shipment-customer-service/internal/domain/apikey"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/tenant"
+	"shipment-customer-service/internal/shipment/apikey"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeKeyRepo struct {
+	apikey.Repository
+	keys map[[sha256.Size]byte]apikeydomain.Key
+}
+
+func (f *fakeKeyRepo) GetAPIKeyByHash(ctx context.Context, hash []byte) (apikeydomain.Key, error) {
+	key, ok := f.keys[[sha256.Size]byte(hash)]
+	if !ok {
+		return apikeydomain.Key{}, sql.ErrNoRows
+	}
+	return key, nil
+}
+
+func (f *fakeKeyRepo) TouchAPIKey(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestAuthenticatorUnary(t *testing.T) {
+	keys := apikey.NewService(&fakeKeyRepo{keys: map[[sha256.Size]byte]apikeydomain.Key{
+		sha256.Sum256([]byte("reader-key")): {ID: "r1", TenantID: "brand-a", Scopes: []auth.Scope{auth.ScopeShipmentsRead}},
+		sha256.Sum256([]byte("tenantless")): {ID: "t1", Scopes: []auth.Scope{auth.ScopeAdmin}},
+	}})
+	interceptor := NewAuthenticator(keys, nil, slog.New(slog.NewTextHandler(io.Discard, nil))).Unary()
+
+	tests := []struct {
+		name          string
+		method        string
+		md            metadata.MD
+		want          codes.Code
+		wantPrincipal string
+		wantTenant    string
+	}{
+		{name: "no credentials", method: shipmentpb.ShipmentService_CreateShipment_FullMethodName, want: codes.Unauthenticated},
+		{name: "no credentials for tenant", method: shipmentpb.ShipmentService_CreateShipment_FullMethodName, md: metadata.Pairs(tenant.MetadataKey, "brand-b"), want: codes.Unauthenticated},
+		{name: "forwarded key", method: shipmentpb.ShipmentService_GetShipment_FullMethodName, md: metadata.Pairs("x-api-key", "reader-key"), want: codes.OK, wantPrincipal: "apikey:r1", wantTenant: "brand-a"},
+		{name: "key asks for another tenant", method: shipmentpb.ShipmentService_GetShipment_FullMethodName, md: metadata.Pairs("x-api-key", "reader-key", tenant.MetadataKey, "brand-b"), want: codes.PermissionDenied},
+		{name: "key without tenant", method: shipmentpb.ShipmentService_GetShipment_FullMethodName, md: metadata.Pairs("x-api-key", "tenantless"), want: codes.PermissionDenied},
+		{name: "unknown key", method: shipmentpb.ShipmentService_GetShipment_FullMethodName, md: metadata.Pairs("x-api-key", "nope"), want: codes.Unauthenticated},
+		{name: "bearer not configured", method: shipmentpb.ShipmentService_GetShipment_FullMethodName, md: metadata.Pairs("authorization", "Bearer a.b.c"), want: codes.Unauthenticated},
+		{name: "missing scope", method: shipmentpb.ShipmentService_CreateShipment_FullMethodName, md: metadata.Pairs("x-api-key", "reader-key"), want: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			var principal, tenantID string
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				principal = auth.PrincipalID(ctx)
+				tenantID, _ = tenant.FromContext(ctx)
+				return nil, nil
+			})
+
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("interceptor code = %v, want %v", got, tt.want)
+			}
+			if principal != tt.wantPrincipal {
+				t.Fatalf("interceptor principal = %q, want %q", principal, tt.wantPrincipal)
+			}
+			if tenantID != tt.wantTenant {
+				t.Fatalf("interceptor tenant = %q, want %q", tenantID, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package grpc
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	customerpb "shipment-customer-service/api/proto"
+	"shipment-customer-service/internal/platform/tenant"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
+)
+
+const watchReconnectDelay = time.Second
+
+// sharedCallTimeout bounds an upstream call shared by concurrent misses,
+// which no single caller's deadline does.
+const sharedCallTimeout = 10 * time.Second
+
+// CachingClient remembers the customer resolved for an IDN of a tenant so
+// repeated shipments for the same customer skip the upsert round trip. Entries expire
+// after the TTL and the least recently used one is evicted once the cache is
+// full. Concurrent misses for one IDN share a single upstream call.
+type CachingClient struct {
+	next  CustomerClient
+	size  int
+	ttl   time.Duration
+	group singleflight.Group
+	now   func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[cacheKey]*list.Element
+	// byCustomer maps customer IDs back to IDNs: erasure events do not carry
+	// the IDN anymore.
+	byCustomer map[string]cacheKey
+
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+}
+
+// cacheKey is per tenant: the same IDN is a different customer in every
+// tenant.
+type cacheKey struct {
+	tenantID string
+	idn      string
+}
+
+type cacheEntry struct {
+	key       cacheKey
+	customer  *customerpb.CustomerResponse
+	expiresAt time.Time
+}
+
+func NewCachingClient(next CustomerClient, size int, ttl time.Duration) (*CachingClient, error) {
+	meter := otel.Meter("shipment-customer-client")
+
+	hits, err := meter.Int64Counter(
+		"customer_client.cache.hits",
+		metric.WithDescription("Customer lookups answered from the local cache"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	misses, err := meter.Int64Counter(
+		"customer_client.cache.misses",
+		metric.WithDescription("Customer lookups forwarded to the customer service"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachingClient{
+		next:       next,
+		size:       size,
+		ttl:        ttl,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[cacheKey]*list.Element),
+		byCustomer: make(map[string]cacheKey),
+		hits:       hits,
+		misses:     misses,
+	}, nil
+}
+
+func (c *CachingClient) UpsertCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	tenantID, _ := tenant.FromContext(ctx)
+	key := cacheKey{tenantID: tenantID, idn: idn}
+	if customer, ok := c.get(key); ok {
+		c.hits.Add(ctx, 1)
+		return customer, nil
+	}
+	c.misses.Add(ctx, 1)
+
+	// The shared call keeps the tenant, request ID and trace of the caller
+	// that started it but not its cancellation, so that caller giving up does
+	// not fail the others; each of them stops waiting when its own context is
+	// done.
+	result := c.group.DoChan(tenantID+"/"+idn, func() (any, error) {
+		callCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedCallTimeout)
+		defer cancel()
+		customer, err := c.next.UpsertCustomer(callCtx, idn)
+		if err != nil {
+			return nil, err
+		}
+		c.put(key, customer)
+		return customer, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return proto.Clone(r.Val.(*customerpb.CustomerResponse)).(*customerpb.CustomerResponse), nil
+	}
+}
+
+// GetCustomer answers from the entries left by UpsertCustomer, which name
+// customers that exist, and otherwise asks the wrapped client. Lookups are
+// not cached: a customer that is not found may be created any moment.
+func (c *CachingClient) GetCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	tenantID, _ := tenant.FromContext(ctx)
+	if customer, ok := c.get(cacheKey{tenantID: tenantID, idn: idn}); ok {
+		c.hits.Add(ctx, 1)
+		return customer, nil
+	}
+	c.misses.Add(ctx, 1)
+	return c.next.GetCustomer(ctx, idn)
+}
+
+// Ready passes through the readiness of the wrapped client.
+func (c *CachingClient) Ready() error {
+	if checker, ok := c.next.(interface{ Ready() error }); ok {
+		return checker.Ready()
+	}
+	return nil
+}
+
+func (c *CachingClient) Invalidate(tenantID, idn string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[cacheKey{tenantID: tenantID, idn: idn}]; ok {
+		c.remove(element)
+	}
+}
+
+func (c *CachingClient) InvalidateCustomer(customerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.byCustomer[customerID]; ok {
+		c.remove(c.entries[key])
+	}
+}
+
+func (c *CachingClient) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+	clear(c.byCustomer)
+}
+
+// RunInvalidation follows the customer change stream of all tenants and drops
+// entries of customers that were updated, merged or erased, until ctx is done. The cache
+// is purged whenever the stream (re)starts without a cursor, since changes
+// made before that point were never seen.
+func (c *CachingClient) RunInvalidation(ctx context.Context, client customerpb.CustomerServiceClient, logger *slog.Logger) {
+	var cursor int64
+	for {
+		err := c.watch(ctx, client, &cursor)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("customer_watch_failed", slog.String("error", err.Error()))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchReconnectDelay):
+		}
+	}
+}
+
+func (c *CachingClient) watch(ctx context.Context, client customerpb.CustomerServiceClient, cursor *int64) error {
+	ctx = tenant.AppendToOutgoingContext(tenant.NewContext(ctx, tenant.All))
+	stream, err := client.WatchCustomers(ctx, &customerpb.WatchCustomersRequest{Cursor: *cursor, FromLatest: *cursor == 0})
+	if err != nil {
+		return err
+	}
+	if *cursor == 0 {
+		c.Purge()
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		*cursor = event.GetCursor()
+
+		switch event.GetType() {
+		case customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_UPDATED,
+			customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_MERGED,
+			customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_ERASED:
+			c.InvalidateCustomer(event.GetCustomerId())
+			if event.GetIdn() != "" {
+				c.Invalidate(event.GetTenantId(), event.GetIdn())
+			}
+		}
+	}
+}
+
+func (c *CachingClient) get(key cacheKey) (*customerpb.CustomerResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return proto.Clone(entry.customer).(*customerpb.CustomerResponse), true
+}
+
+func (c *CachingClient) put(key cacheKey, customer *customerpb.CustomerResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	entry := &cacheEntry{key: key, customer: customer, expiresAt: c.now().Add(c.ttl)}
+	c.entries[key] = c.order.PushFront(entry)
+	c.byCustomer[customer.GetId()] = key
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove must be called with c.mu held.
+func (c *CachingClient) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.key)
+	if c.byCustomer[entry.customer.GetId()] == entry.key {
+		delete(c.byCustomer, entry.customer.GetId())
+	}
+}
@@ -0,0 +1,180 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	customerpb "shipment-customer-service/api/proto"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+func newTestCache(t *testing.T, next CustomerClient, size int) *CachingClient {
+	t.Helper()
+	cache, err := NewCachingClient(next, size, time.Minute)
+	if err != nil {
+		t.Fatalf("NewCachingClient() error = %v", err)
+	}
+	return cache
+}
+
+func customerFor(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	return &customerpb.CustomerResponse{Id: "customer-" + idn, Idn: idn}, nil
+}
+
+func TestCachingClientHitsAndExpiry(t *testing.T) {
+	next := &mockCustomerClient{upsertFn: customerFor}
+	cache := newTestCache(t, next, 10)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for range 3 {
+		customer, err := cache.UpsertCustomer(context.Background(), "123456789012")
+		if err != nil {
+			t.Fatalf("UpsertCustomer() error = %v", err)
+		}
+		if customer.GetId() != "customer-123456789012" {
+			t.Fatalf("UpsertCustomer() id = %q, want %q", customer.GetId(), "customer-123456789012")
+		}
+	}
+	if next.calls != 1 {
+		t.Fatalf("upstream calls = %d, want 1", next.calls)
+	}
+
+	now = now.Add(time.Minute)
+	cache.UpsertCustomer(context.Background(), "123456789012")
+	if next.calls != 2 {
+		t.Fatalf("upstream calls after expiry = %d, want 2", next.calls)
+	}
+}
+
+func TestCachingClientEvictsLeastRecentlyUsed(t *testing.T) {
+	next := &mockCustomerClient{upsertFn: customerFor}
+	cache := newTestCache(t, next, 2)
+	ctx := context.Background()
+
+	cache.UpsertCustomer(ctx, "111111111111")
+	cache.UpsertCustomer(ctx, "222222222222")
+	cache.UpsertCustomer(ctx, "111111111111")
+	cache.UpsertCustomer(ctx, "333333333333")
+
+	calls := next.calls
+	cache.UpsertCustomer(ctx, "111111111111")
+	if next.calls != calls {
+		t.Fatalf("recently used entry was evicted")
+	}
+	cache.UpsertCustomer(ctx, "222222222222")
+	if next.calls != calls+1 {
+		t.Fatalf("least recently used entry was not evicted")
+	}
+}
+
+func TestCachingClientInvalidateCustomer(t *testing.T) {
+	next := &mockCustomerClient{upsertFn: customerFor}
+	cache := newTestCache(t, next, 10)
+	ctx := context.Background()
+
+	cache.UpsertCustomer(ctx, "123456789012")
+	cache.InvalidateCustomer("customer-123456789012")
+	cache.UpsertCustomer(ctx, "123456789012")
+
+	if next.calls != 2 {
+		t.Fatalf("upstream calls = %d, want 2", next.calls)
+	}
+}
+
+func TestCachingClientKeepsTenantsApart(t *testing.T) {
+	next := &mockCustomerClient{upsertFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+		tenantID, _ := tenant.FromContext(ctx)
+		return &customerpb.CustomerResponse{Id: tenantID + "-" + idn, Idn: idn}, nil
+	}}
+	cache := newTestCache(t, next, 10)
+	brandA := tenant.NewContext(context.Background(), "brand-a")
+	brandB := tenant.NewContext(context.Background(), "brand-b")
+
+	cache.UpsertCustomer(brandA, "123456789012")
+	customer, _ := cache.UpsertCustomer(brandB, "123456789012")
+
+	if next.calls != 2 || customer.GetId() != "brand-b-123456789012" {
+		t.Fatalf("UpsertCustomer() = %q after %d upstream calls, want brand-b-123456789012 after 2", customer.GetId(), next.calls)
+	}
+
+	cache.Invalidate("brand-a", "123456789012")
+	cache.UpsertCustomer(brandB, "123456789012")
+	if next.calls != 2 {
+		t.Fatalf("upstream calls = %d after invalidating another tenant, want 2", next.calls)
+	}
+}
+
+func TestCachingClientCoalescesConcurrentMisses(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	next := &mockCustomerClient{}
+	next.upsertFn = func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+		calls.Add(1)
+		<-release
+		return customerFor(ctx, idn)
+	}
+	cache := newTestCache(t, next, 10)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.UpsertCustomer(context.Background(), "123456789012"); err != nil {
+				t.Errorf("UpsertCustomer() error = %v", err)
+			}
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("upstream calls = %d, want 1", got)
+	}
+}
+
+func TestCachingClientSharedCallOutlivesFirstCaller(t *testing.T) {
+	release := make(chan struct{})
+	next := &mockCustomerClient{}
+	next.upsertFn = func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+		<-release
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if id, _ := tenant.FromContext(ctx); id != "brand-a" {
+			return nil, tenant.ErrMissing
+		}
+		return customerFor(ctx, idn)
+	}
+	cache := newTestCache(t, next, 10)
+
+	first, cancel := context.WithCancel(tenant.NewContext(context.Background(), "brand-a"))
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := cache.UpsertCustomer(first, "123456789012")
+		firstDone <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	secondDone := make(chan error, 1)
+	go func() {
+		_, err := cache.UpsertCustomer(tenant.NewContext(context.Background(), "brand-a"), "123456789012")
+		secondDone <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-firstDone; err != context.Canceled {
+		t.Fatalf("UpsertCustomer() error = %v for the cancelled caller, want %v", err, context.Canceled)
+	}
+	close(release)
+	if err := <-secondDone; err != nil {
+		t.Fatalf("UpsertCustomer() error = %v for the waiting caller", err)
+	}
+}
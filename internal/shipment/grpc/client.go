@@ -4,20 +4,31 @@ import (
 	"context"
 
 	customerpb "shipment-customer-service/api/proto"
+	"shipment-customer-service/internal/platform/tenant"
 
 	"google.golang.org/grpc"
 )
 
 type CustomerClient interface {
 	UpsertCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error)
+	// GetCustomer looks the customer up without creating it; a missing one
+	// is a NotFound status.
+	GetCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error)
 }
 
 type GRPCClient struct {
 	client customerpb.CustomerServiceClient
 }
 
+// UpsertCustomer creates or finds the customer in the tenant of ctx, which is
+// passed on in the call metadata.
 func (c *GRPCClient) UpsertCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
-	return c.client.UpsertCustomer(ctx, &customerpb.UpsertCustomerRequest{Idn: idn})
+	return c.client.UpsertCustomer(tenant.AppendToOutgoingContext(ctx), &customerpb.UpsertCustomerRequest{Idn: idn})
+}
+
+// GetCustomer finds the customer in the tenant of ctx.
+func (c *GRPCClient) GetCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	return c.client.GetCustomer(tenant.AppendToOutgoingContext(ctx), &customerpb.GetCustomerRequest{Idn: idn})
 }
 
 func NewCustomerClientService(conn *grpc.ClientConn) *GRPCClient {
@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	customerpb "shipment-customer-service/api/proto"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InstrumentedClient records the latency and outcome of customer lookups as
+// the shipment service sees them: cache hits, retries and calls rejected by
+// an open circuit included. Per-attempt RPC metrics come from otelgrpc.
+type InstrumentedClient struct {
+	next     CustomerClient
+	duration metric.Float64Histogram
+}
+
+func NewInstrumentedClient(next CustomerClient) (*InstrumentedClient, error) {
+	duration, err := otel.Meter("shipment-customer-client").Float64Histogram(
+		"customer_client.duration",
+		metric.WithDescription("Customer lookups by method and gRPC status code"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &InstrumentedClient{next: next, duration: duration}, nil
+}
+
+func (c *InstrumentedClient) UpsertCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	start := time.Now()
+	customer, err := c.next.UpsertCustomer(ctx, idn)
+	c.record(ctx, "UpsertCustomer", start, err)
+	return customer, err
+}
+
+func (c *InstrumentedClient) GetCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	start := time.Now()
+	customer, err := c.next.GetCustomer(ctx, idn)
+	c.record(ctx, "GetCustomer", start, err)
+	return customer, err
+}
+
+func (c *InstrumentedClient) record(ctx context.Context, method string, start time.Time, err error) {
+	c.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("rpc.method", method),
+		attribute.String("rpc.response.status_code", statusCode(err).String()),
+	))
+}
+
+// statusCode gives errors raised on this side of the call, a closed context
+// or an open circuit, the code they would have had from the server.
+func statusCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return codes.Unavailable
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Code()
+	}
+	return status.Code(err)
+}
+
+// Ready passes through the readiness of the wrapped client.
+func (c *InstrumentedClient) Ready() error {
+	if checker, ok := c.next.(interface{ Ready() error }); ok {
+		return checker.Ready()
+	}
+	return nil
+}
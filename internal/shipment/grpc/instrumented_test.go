@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "success", want: codes.OK},
+		{name: "server status", err: status.Error(codes.InvalidArgument, "bad idn"), want: codes.InvalidArgument},
+		{name: "open circuit", err: fmt.Errorf("upsert: %w", ErrCircuitOpen), want: codes.Unavailable},
+		{name: "deadline", err: context.DeadlineExceeded, want: codes.DeadlineExceeded},
+		{name: "cancelled", err: context.Canceled, want: codes.Canceled},
+		{name: "other", err: errors.New("boom"), want: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusCode(tt.err); got != tt.want {
+				t.Fatalf("statusCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
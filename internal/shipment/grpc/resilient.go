@@ -0,0 +1,241 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	customerpb "shipment-customer-service/api/proto"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrCircuitOpen = errors.New("customer service circuit open")
+
+type ResilienceConfig struct {
+	// CallTimeout bounds every single attempt, not the whole call.
+	CallTimeout time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// FailureThreshold consecutive failures open the breaker; after
+	// OpenTimeout one probe call is let through to test recovery.
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+func DefaultResilienceConfig() ResilienceConfig {
+	return ResilienceConfig{
+		CallTimeout:      2 * time.Second,
+		MaxAttempts:      3,
+		BaseBackoff:      100 * time.Millisecond,
+		MaxBackoff:       time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      10 * time.Second,
+	}
+}
+
+// ResilientClient decorates a CustomerClient with per-attempt deadlines,
+// jittered retries and a circuit breaker. Retrying is safe because
+// UpsertCustomer is idempotent and GetCustomer only reads.
+type ResilientClient struct {
+	next    CustomerClient
+	cfg     ResilienceConfig
+	breaker *Breaker
+	retries metric.Int64Counter
+}
+
+func NewResilientClient(next CustomerClient, cfg ResilienceConfig) (*ResilientClient, error) {
+	meter := otel.Meter("shipment-customer-client")
+
+	retries, err := meter.Int64Counter(
+		"customer_client.retries",
+		metric.WithDescription("Retried customer service calls"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	breaker := NewBreaker(cfg.FailureThreshold, cfg.OpenTimeout)
+	if _, err := meter.Int64ObservableGauge(
+		"customer_client.circuit_breaker.state",
+		metric.WithDescription("Circuit breaker state: 0 closed, 1 half-open, 2 open"),
+		metric.WithInt64Callback(func(ctx context.Context, o metric.Int64Observer) error {
+			o.Observe(int64(breaker.State()))
+			return nil
+		}),
+	); err != nil {
+		return nil, err
+	}
+
+	return &ResilientClient{next: next, cfg: cfg, breaker: breaker, retries: retries}, nil
+}
+
+func (c *ResilientClient) UpsertCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	return c.call(ctx, idn, c.next.UpsertCustomer)
+}
+
+func (c *ResilientClient) GetCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	return c.call(ctx, idn, c.next.GetCustomer)
+}
+
+func (c *ResilientClient) call(ctx context.Context, idn string, method func(context.Context, string) (*customerpb.CustomerResponse, error)) (*customerpb.CustomerResponse, error) {
+	for attempt := 1; ; attempt++ {
+		if !c.breaker.Allow() {
+			return nil, status.Error(codes.Unavailable, ErrCircuitOpen.Error())
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, c.cfg.CallTimeout)
+		resp, err := method(callCtx, idn)
+		cancel()
+
+		if err != nil && ctx.Err() != nil {
+			// The caller gave up: the failure says nothing about the service.
+			c.breaker.Release()
+			return nil, err
+		}
+		if err == nil || !retryable(err) {
+			// Any answer other than unavailability proves the service is up.
+			c.breaker.Success()
+			return resp, err
+		}
+		c.breaker.Failure()
+
+		if attempt >= c.cfg.MaxAttempts {
+			return nil, err
+		}
+
+		c.retries.Add(ctx, 1)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(jitteredBackoff(attempt, c.cfg.BaseBackoff, c.cfg.MaxBackoff)):
+		}
+	}
+}
+
+// Ready reports ErrCircuitOpen while the breaker is rejecting calls.
+func (c *ResilientClient) Ready() error {
+	if c.breaker.State() == BreakerOpen {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// jitteredBackoff picks a uniformly random delay up to base*2^(attempt-1),
+// capped at max ("full jitter"), so retrying clients do not synchronise.
+func jitteredBackoff(attempt int, base, max time.Duration) time.Duration {
+	ceiling := base << (attempt - 1)
+	if ceiling <= 0 || ceiling > max {
+		ceiling = max
+	}
+	return rand.N(ceiling + 1)
+}
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerHalfOpen
+	BreakerOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "open"
+	}
+}
+
+// Breaker is a consecutive-failure circuit breaker. While half-open only a
+// single probe call is allowed at a time.
+type Breaker struct {
+	mu          sync.Mutex
+	threshold   int
+	openTimeout time.Duration
+	failures    int
+	openedAt    time.Time
+	open        bool
+	probing     bool
+	now         func() time.Time
+}
+
+func NewBreaker(threshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{threshold: threshold, openTimeout: openTimeout, now: time.Now}
+}
+
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.openTimeout {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.open = false
+	b.probing = false
+}
+
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.probing || b.failures >= b.threshold {
+		b.open = true
+		b.openedAt = b.now()
+	}
+	b.probing = false
+}
+
+// Release ends a call allowed by Allow without an outcome, leaving the
+// breaker as it was; a half-open breaker lets the next probe through.
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case !b.open:
+		return BreakerClosed
+	case b.probing || b.now().Sub(b.openedAt) >= b.openTimeout:
+		return BreakerHalfOpen
+	default:
+		return BreakerOpen
+	}
+}
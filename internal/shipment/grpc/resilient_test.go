@@ -0,0 +1,185 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customerpb "shipment-customer-service/api/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockCustomerClient struct {
+	calls    int
+	upsertFn func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error)
+	getFn    func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error)
+}
+
+func (m *mockCustomerClient) UpsertCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	m.calls++
+	return m.upsertFn(ctx, idn)
+}
+
+func (m *mockCustomerClient) GetCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	m.calls++
+	return m.getFn(ctx, idn)
+}
+
+func testConfig() ResilienceConfig {
+	return ResilienceConfig{
+		CallTimeout:      time.Second,
+		MaxAttempts:      3,
+		BaseBackoff:      time.Millisecond,
+		MaxBackoff:       time.Millisecond,
+		FailureThreshold: 3,
+		OpenTimeout:      time.Minute,
+	}
+}
+
+func TestResilientClientRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantCode  codes.Code
+	}{
+		{name: "success", errs: []error{nil}, wantCalls: 1, wantCode: codes.OK},
+		{name: "recovers after unavailable", errs: []error{status.Error(codes.Unavailable, "down"), nil}, wantCalls: 2, wantCode: codes.OK},
+		{name: "retries deadline exceeded", errs: []error{status.Error(codes.DeadlineExceeded, "slow"), status.Error(codes.DeadlineExceeded, "slow"), nil}, wantCalls: 3, wantCode: codes.OK},
+		{name: "gives up after max attempts", errs: []error{status.Error(codes.Unavailable, "down"), status.Error(codes.Unavailable, "down"), status.Error(codes.Unavailable, "down")}, wantCalls: 3, wantCode: codes.Unavailable},
+		{name: "no retry on invalid argument", errs: []error{status.Error(codes.InvalidArgument, "bad idn")}, wantCalls: 1, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &mockCustomerClient{}
+			next.upsertFn = func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+				if err := tt.errs[next.calls-1]; err != nil {
+					return nil, err
+				}
+				return &customerpb.CustomerResponse{Id: "customer-1"}, nil
+			}
+
+			client, err := NewResilientClient(next, testConfig())
+			if err != nil {
+				t.Fatalf("NewResilientClient() error = %v", err)
+			}
+
+			_, err = client.UpsertCustomer(context.Background(), "123456789012")
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("UpsertCustomer() code = %v, want %v", got, tt.wantCode)
+			}
+			if next.calls != tt.wantCalls {
+				t.Fatalf("UpsertCustomer() calls = %d, want %d", next.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestResilientClientOpensCircuit(t *testing.T) {
+	next := &mockCustomerClient{upsertFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+		return nil, status.Error(codes.Unavailable, "down")
+	}}
+
+	client, err := NewResilientClient(next, testConfig())
+	if err != nil {
+		t.Fatalf("NewResilientClient() error = %v", err)
+	}
+
+	client.UpsertCustomer(context.Background(), "123456789012")
+	if err := client.Ready(); err != ErrCircuitOpen {
+		t.Fatalf("Ready() error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	calls := next.calls
+	_, err = client.UpsertCustomer(context.Background(), "123456789012")
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("UpsertCustomer() code = %v, want %v", status.Code(err), codes.Unavailable)
+	}
+	if next.calls != calls {
+		t.Fatalf("UpsertCustomer() reached the backend while the circuit was open")
+	}
+}
+
+func TestResilientClientIgnoresCallerCancellation(t *testing.T) {
+	tests := []struct {
+		name string
+		open bool
+	}{
+		{name: "closed"},
+		{name: "half-open", open: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &mockCustomerClient{}
+			client, err := NewResilientClient(next, testConfig())
+			if err != nil {
+				t.Fatalf("NewResilientClient() error = %v", err)
+			}
+			now := time.Now()
+			client.breaker.now = func() time.Time { return now }
+			if tt.open {
+				for range testConfig().FailureThreshold {
+					client.breaker.Failure()
+				}
+				now = now.Add(testConfig().OpenTimeout)
+			}
+			before := client.breaker.State()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			next.upsertFn = func(callCtx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+				cancel()
+				return nil, status.FromContextError(callCtx.Err()).Err()
+			}
+			if _, err := client.UpsertCustomer(ctx, "123456789012"); status.Code(err) != codes.Canceled {
+				t.Fatalf("UpsertCustomer() code = %v, want %v", status.Code(err), codes.Canceled)
+			}
+
+			if got := client.breaker.State(); got != before {
+				t.Fatalf("State() = %v after a cancelled call, want %v", got, before)
+			}
+			if !client.breaker.Allow() {
+				t.Fatalf("Allow() = false after a cancelled call")
+			}
+		})
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	breaker.Failure()
+	breaker.Failure()
+	if got := breaker.State(); got != BreakerOpen {
+		t.Fatalf("State() = %v, want %v", got, BreakerOpen)
+	}
+
+	now = now.Add(time.Minute)
+	if got := breaker.State(); got != BreakerHalfOpen {
+		t.Fatalf("State() = %v, want %v", got, BreakerHalfOpen)
+	}
+	if !breaker.Allow() {
+		t.Fatalf("Allow() = false, want true for the probe")
+	}
+	if breaker.Allow() {
+		t.Fatalf("Allow() = true, want false while the probe is in flight")
+	}
+
+	// A failed probe reopens the circuit for another full timeout.
+	breaker.Failure()
+	if got := breaker.State(); got != BreakerOpen {
+		t.Fatalf("State() = %v, want %v", got, BreakerOpen)
+	}
+
+	now = now.Add(time.Minute)
+	breaker.Allow()
+	breaker.Success()
+	if got := breaker.State(); got != BreakerClosed {
+		t.Fatalf("State() = %v, want %v", got, BreakerClosed)
+	}
+}
@@ -0,0 +1,324 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	shipmentpb "shipment-customer-service/api/proto/shipment"
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/gateway"
+	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/platform/tenant"
+	"shipment-customer-service/internal/shipment/stream"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ShipmentService is the part of shipment/service.Service the gRPC server
+// needs. It is declared here because that package already depends on this
+// one for CustomerClient.
+type ShipmentService interface {
+	Create(ctx context.Context, input domain.CreateShipmentInput) (domain.Shipment, error)
+	Get(ctx context.Context, id string) (domain.Shipment, error)
+	List(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error)
+	Transition(ctx context.Context, id, status string) (domain.Shipment, error)
+	Cancel(ctx context.Context, id string) (domain.Shipment, error)
+	AuthorizeFeed(ctx context.Context, customerID string) (string, error)
+}
+
+type Server struct {
+	shipmentpb.UnimplementedShipmentServiceServer
+	service ShipmentService
+	streams *stream.Broker
+	logger  *slog.Logger
+}
+
+func NewServer(service ShipmentService, streams *stream.Broker, logger *slog.Logger) *Server {
+	return &Server{service: service, streams: streams, logger: logger}
+}
+
+func (s *Server) CreateShipment(ctx context.Context, req *shipmentpb.CreateShipmentRequest) (*shipmentpb.Shipment, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+
+	shipment, err := s.service.Create(ctx, domain.CreateShipmentInput{
+		Route:       req.GetRoute(),
+		Price:       req.GetPrice(),
+		CustomerIDN: req.GetCustomer().GetIdn(),
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	s.logger.Info(
+		"shipment_created",
+		slog.String("shipment_id", shipment.ID),
+		slog.String("trace_id", telemetry.TraceID(ctx)),
+	)
+
+	code := http.StatusCreated
+	if shipment.Status == domain.StatusPendingCustomer {
+		code = http.StatusAccepted
+	}
+	// Only fails outside a real gRPC call; the code just shapes the REST
+	// response.
+	_ = gateway.SetStatus(ctx, code)
+
+	return toProto(shipment), nil
+}
+
+func (s *Server) GetShipment(ctx context.Context, req *shipmentpb.GetShipmentRequest) (*shipmentpb.Shipment, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+
+	shipment, err := s.service.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	s.logger.Info(
+		"shipment_fetched",
+		slog.String("shipment_id", shipment.ID),
+		slog.String("trace_id", telemetry.TraceID(ctx)),
+	)
+
+	return toProto(shipment), nil
+}
+
+// ListShipments pages through shipments newest first. The page token is the
+// ID of the last shipment on the previous page.
+func (s *Server) ListShipments(ctx context.Context, req *shipmentpb.ListShipmentsRequest) (*shipmentpb.ListShipmentsResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+	if req.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+
+	limit := int(req.GetPageSize())
+	if limit == 0 {
+		limit = domain.DefaultListLimit
+	}
+	limit = min(limit, domain.MaxListLimit)
+
+	shipments, err := s.service.List(ctx, domain.ListShipmentsFilter{
+		CustomerID: req.GetCustomerId(),
+		Status:     req.GetStatus(),
+		Limit:      limit,
+		After:      req.GetPageToken(),
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	response := &shipmentpb.ListShipmentsResponse{}
+	for _, shipment := range shipments {
+		response.Shipments = append(response.Shipments, toProto(shipment))
+	}
+	// A short page is the last one; a full page may be followed by an empty
+	// one, which is cheaper than counting.
+	if len(shipments) == limit {
+		response.NextPageToken = shipments[len(shipments)-1].ID
+	}
+
+	return response, nil
+}
+
+func (s *Server) TransitionShipment(ctx context.Context, req *shipmentpb.TransitionShipmentRequest) (*shipmentpb.Shipment, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+
+	shipment, err := s.service.Transition(ctx, req.GetId(), req.GetStatus())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	s.logger.Info(
+		"shipment_status_changed",
+		slog.String("shipment_id", shipment.ID),
+		slog.String("status", shipment.Status),
+		slog.String("trace_id", telemetry.TraceID(ctx)),
+	)
+
+	return toProto(shipment), nil
+}
+
+func (s *Server) CancelShipment(ctx context.Context, req *shipmentpb.CancelShipmentRequest) (*shipmentpb.Shipment, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+
+	shipment, err := s.service.Cancel(ctx, req.GetId())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	s.logger.Info(
+		"shipment_status_changed",
+		slog.String("shipment_id", shipment.ID),
+		slog.String("status", shipment.Status),
+		slog.String("trace_id", telemetry.TraceID(ctx)),
+	)
+
+	return toProto(shipment), nil
+}
+
+// WatchShipments streams shipment events like the SSE endpoints do: the whole
+// stored backlog after cursor first, then live ones, skipping duplicates by
+// cursor. Outbox seqs are handed out in commit order, so an event at or below
+// the cursor has already been sent.
+func (s *Server) WatchShipments(req *shipmentpb.WatchShipmentsRequest, srv grpc.ServerStreamingServer[shipmentpb.ShipmentEvent]) error {
+	ctx := srv.Context()
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "request is required")
+	}
+	if req.GetCursor() < 0 {
+		return status.Error(codes.InvalidArgument, "cursor must not be negative")
+	}
+	if req.GetCustomerId() != "" {
+		if _, err := uuid.Parse(req.GetCustomerId()); err != nil {
+			return mapError(domain.ErrInvalidCustomerID)
+		}
+	}
+	// Whoever may read a shipment may follow it; wider feeds need list
+	// access and are narrowed to the caller's own customer if need be.
+	customerID := req.GetCustomerId()
+	if req.GetShipmentId() != "" {
+		if _, err := s.service.Get(ctx, req.GetShipmentId()); err != nil {
+			return mapError(err)
+		}
+	} else {
+		var err error
+		if customerID, err = s.service.AuthorizeFeed(ctx, customerID); err != nil {
+			return mapError(err)
+		}
+	}
+
+	tenantID, _ := tenant.FromContext(ctx)
+	filter := stream.Filter{TenantID: tenantID, ShipmentID: req.GetShipmentId(), CustomerID: customerID}
+	sub := s.streams.Subscribe(filter)
+	defer sub.Close()
+
+	cursor := req.GetCursor()
+	if cursor > 0 {
+		var err, sendErr error
+		cursor, err = s.streams.Replay(ctx, cursor, filter, func(event domain.Event) error {
+			sendErr = srv.Send(toProtoEvent(event))
+			return sendErr
+		})
+		if sendErr != nil {
+			return sendErr
+		}
+		if err != nil {
+			return mapError(err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, "event stream interrupted, resume from the last cursor")
+			}
+			if event.Seq <= cursor {
+				continue
+			}
+			if err := srv.Send(toProtoEvent(event)); err != nil {
+				return err
+			}
+			cursor = event.Seq
+		}
+	}
+}
+
+func toProto(shipment domain.Shipment) *shipmentpb.Shipment {
+	return &shipmentpb.Shipment{
+		Id:         shipment.ID,
+		Route:      shipment.Route,
+		Price:      shipment.Price,
+		Status:     shipment.Status,
+		CustomerId: shipment.CustomerID,
+		CreatedAt:  shipment.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toProtoEvent(event domain.Event) *shipmentpb.ShipmentEvent {
+	return &shipmentpb.ShipmentEvent{
+		Cursor:         event.Seq,
+		EventId:        event.ID,
+		Type:           event.Type,
+		ShipmentId:     event.ShipmentID,
+		CustomerId:     event.Data.CustomerID,
+		Route:          event.Data.Route,
+		Price:          event.Data.Price,
+		Status:         event.Data.Status,
+		PreviousStatus: event.Data.PreviousStatus,
+		OccurredAt:     event.OccurredAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func mapError(err error) error {
+	var validation *domain.ValidationError
+	if errors.As(err, &validation) {
+		return validationStatus(validation)
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidRoute),
+		errors.Is(err, domain.ErrInvalidPrice),
+		errors.Is(err, domain.ErrInvalidIDN),
+		errors.Is(err, domain.ErrInvalidShipmentID),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidCustomerID),
+		errors.Is(err, domain.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrStatusConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	// Errors from the customer service keep their code.
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Internal, "internal error")
+}
+
+// validationStatus reports every invalid field as a BadRequest violation;
+// field paths are the JSON pointers of the v1 REST API.
+func validationStatus(err *domain.ValidationError) error {
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(err.Fields))
+	for i, field := range err.Fields {
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       field.Pointer,
+			Description: field.Detail,
+			Reason:      field.Code,
+		}
+	}
+
+	st, detailErr := status.New(codes.InvalidArgument, err.Error()).
+		WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"testing"
+
+	shipmentpb "shipment-customer-service/api/proto/shipment"
+	domain "shipment-customer-service/internal/domain/shipment"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockShipmentService struct {
+	ShipmentService
+	listFn       func(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error)
+	transitionFn func(ctx context.Context, id, status string) (domain.Shipment, error)
+	createFn     func(ctx context.Context, input domain.CreateShipmentInput) (domain.Shipment, error)
+}
+
+func (m *mockShipmentService) Create(ctx context.Context, input domain.CreateShipmentInput) (domain.Shipment, error) {
+	return m.createFn(ctx, input)
+}
+
+func (m *mockShipmentService) List(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error) {
+	return m.listFn(ctx, filter)
+}
+
+func (m *mockShipmentService) Transition(ctx context.Context, id, status string) (domain.Shipment, error) {
+	return m.transitionFn(ctx, id, status)
+}
+
+func shipments(n int) []domain.Shipment {
+	result := make([]domain.Shipment, n)
+	for i := range result {
+		result[i] = domain.Shipment{ID: "s" + strconv.Itoa(i+1)}
+	}
+	return result
+}
+
+func TestListShipmentsPaging(t *testing.T) {
+	tests := []struct {
+		name      string
+		pageSize  int32
+		returned  int
+		wantLimit int
+		wantToken string
+	}{
+		{name: "default page size", pageSize: 0, returned: 50, wantLimit: 50, wantToken: "s50"},
+		{name: "capped page size", pageSize: 1000, returned: 100, wantLimit: 100, wantToken: "s100"},
+		{name: "last page", pageSize: 10, returned: 3, wantLimit: 10, wantToken: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLimit int
+			server := NewServer(&mockShipmentService{listFn: func(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error) {
+				gotLimit = filter.Limit
+				return shipments(tt.returned), nil
+			}}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			resp, err := server.ListShipments(context.Background(), &shipmentpb.ListShipmentsRequest{PageSize: tt.pageSize})
+			if err != nil {
+				t.Fatalf("ListShipments() error = %v", err)
+			}
+			if gotLimit != tt.wantLimit {
+				t.Fatalf("ListShipments() limit = %d, want %d", gotLimit, tt.wantLimit)
+			}
+			if resp.GetNextPageToken() != tt.wantToken {
+				t.Fatalf("ListShipments() next_page_token = %q, want %q", resp.GetNextPageToken(), tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestServerMapsErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{err: domain.ErrInvalidStatus, want: codes.InvalidArgument},
+		{err: domain.ErrNotFound, want: codes.NotFound},
+		{err: domain.ErrForbidden, want: codes.PermissionDenied},
+		{err: domain.ErrInvalidTransition, want: codes.FailedPrecondition},
+		{err: domain.ErrStatusConflict, want: codes.Aborted},
+		{err: status.Error(codes.Unavailable, "customer service down"), want: codes.Unavailable},
+		{err: io.ErrUnexpectedEOF, want: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			server := NewServer(&mockShipmentService{transitionFn: func(ctx context.Context, id, status string) (domain.Shipment, error) {
+				return domain.Shipment{}, tt.err
+			}}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			_, err := server.TransitionShipment(context.Background(), &shipmentpb.TransitionShipmentRequest{Id: "s1", Status: "DELIVERED"})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("TransitionShipment() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerReportsFieldViolations(t *testing.T) {
+	server := NewServer(&mockShipmentService{createFn: func(ctx context.Context, input domain.CreateShipmentInput) (domain.Shipment, error) {
+		return domain.Shipment{}, input.Validate()
+	}}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := server.CreateShipment(context.Background(), &shipmentpb.CreateShipmentRequest{Route: "A-B"})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("CreateShipment() code = %v, want %v", st.Code(), codes.InvalidArgument)
+	}
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField()+" "+violation.GetReason())
+			}
+		}
+	}
+	want := []string{"/price " + domain.CodeInvalidPrice, "/customer/idn " + domain.CodeInvalidIDN}
+	if !slices.Equal(fields, want) {
+		t.Fatalf("CreateShipment() violations = %v, want %v", fields, want)
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	apikey "shipment-customer-service/internal/domain/apikey"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/telemetry"
+)
+
+func (h *Handler) issueAPIKey(w http.ResponseWriter, r *http.Request) {
+	var request apikey.IssueKeyRequest
+	if err := decodeStrict(r, &request); err != nil {
+		writeProblem(w, r, invalidBodyProblem)
+		return
+	}
+
+	scopes := make([]auth.Scope, len(request.Scopes))
+	for i, scope := range request.Scopes {
+		scopes[i] = auth.Scope(scope)
+	}
+
+	key, secret, err := h.keys.Issue(r.Context(), apikey.IssueKeyInput{Name: request.Name, Scopes: scopes})
+	if err != nil {
+		writeProblem(w, r, mapAPIKeyError(err))
+		return
+	}
+
+	h.logger.Info(
+		"api_key_issued",
+		slog.String("api_key_id", key.ID),
+		slog.String("principal", auth.PrincipalID(r.Context())),
+		slog.String("trace_id", telemetry.TraceID(r.Context())),
+	)
+
+	response := toKeyResponse(key)
+	response.Key = secret
+	writeJSON(w, http.StatusCreated, response)
+}
+
+func (h *Handler) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.keys.List(r.Context())
+	if err != nil {
+		writeProblem(w, r, mapAPIKeyError(err))
+		return
+	}
+
+	response := make([]apikey.KeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, toKeyResponse(key))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) revokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	key, err := h.keys.Revoke(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, mapAPIKeyError(err))
+		return
+	}
+
+	h.logger.Info(
+		"api_key_revoked",
+		slog.String("api_key_id", key.ID),
+		slog.String("principal", auth.PrincipalID(r.Context())),
+		slog.String("trace_id", telemetry.TraceID(r.Context())),
+	)
+
+	writeJSON(w, http.StatusOK, toKeyResponse(key))
+}
+
+func toKeyResponse(key apikey.Key) apikey.KeyResponse {
+	response := apikey.KeyResponse{
+		ID:        key.ID,
+		TenantID:  key.TenantID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    make([]string, len(key.Scopes)),
+		CreatedAt: key.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	for i, scope := range key.Scopes {
+		response.Scopes[i] = string(scope)
+	}
+	if key.LastUsedAt != nil {
+		response.LastUsedAt = key.LastUsedAt.UTC().Format(time.RFC3339)
+	}
+	if key.RevokedAt != nil {
+		response.RevokedAt = key.RevokedAt.UTC().Format(time.RFC3339)
+	}
+	return response
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/audit"
+)
+
+// listAudit pages through the audit log of the caller's tenant, oldest first.
+// Pass next_after from a full page as after to get the next one.
+func (h *Handler) listAudit(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeProblem(w, r, mapAuditError(err))
+		return
+	}
+
+	entries, err := h.audit.List(r.Context(), filter)
+	if err != nil {
+		writeProblem(w, r, mapAuditError(err))
+		return
+	}
+
+	response := audit.EntriesResponse{Entries: make([]audit.EntryResponse, len(entries))}
+	for i, entry := range entries {
+		response.Entries[i] = toAuditEntryResponse(entry)
+	}
+	if len(entries) == filter.PageSize() {
+		response.NextAfter = entries[len(entries)-1].Seq
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// verifyAudit checks a page of the hash chain of the caller's tenant. Pass
+// next_after and hash from a full, valid page as after and hash to check the
+// next one.
+func (h *Handler) verifyAudit(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseVerifyFilter(r)
+	if err != nil {
+		writeProblem(w, r, mapAuditError(err))
+		return
+	}
+
+	result, err := h.audit.Verify(r.Context(), filter)
+	if err != nil {
+		writeProblem(w, r, mapAuditError(err))
+		return
+	}
+
+	response := audit.ChainResponse{Entries: result.Entries, Valid: result.Valid, BrokenAt: result.BrokenAt, Hash: result.Last.Hash}
+	if result.Valid && result.Entries == filter.PageSize() {
+		response.NextAfter = result.Last.Seq
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func parseVerifyFilter(r *http.Request) (audit.VerifyFilter, error) {
+	query := r.URL.Query()
+	filter := audit.VerifyFilter{After: audit.Checkpoint{Hash: query.Get("hash")}}
+
+	var err error
+	if value := query.Get("after"); value != "" {
+		if filter.After.Seq, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return audit.VerifyFilter{}, audit.ErrInvalidFilter
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil {
+			return audit.VerifyFilter{}, audit.ErrInvalidFilter
+		}
+	}
+	return filter, nil
+}
+
+func parseAuditFilter(r *http.Request) (audit.Filter, error) {
+	query := r.URL.Query()
+	filter := audit.Filter{
+		EntityType: query.Get("entity_type"),
+		EntityID:   query.Get("entity_id"),
+		Actor:      query.Get("actor"),
+		RequestID:  query.Get("request_id"),
+	}
+
+	var err error
+	if value := query.Get("from"); value != "" {
+		if filter.From, err = time.Parse(time.RFC3339, value); err != nil {
+			return audit.Filter{}, audit.ErrInvalidFilter
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if filter.To, err = time.Parse(time.RFC3339, value); err != nil {
+			return audit.Filter{}, audit.ErrInvalidFilter
+		}
+	}
+	if value := query.Get("after"); value != "" {
+		if filter.AfterSeq, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return audit.Filter{}, audit.ErrInvalidFilter
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil {
+			return audit.Filter{}, audit.ErrInvalidFilter
+		}
+	}
+	return filter, nil
+}
+
+func mapAuditError(err error) problem {
+	if errors.Is(err, audit.ErrInvalidFilter) {
+		return newProblem(http.StatusBadRequest, domain.CodeInvalidAuditFilter)
+	}
+	return internalProblem
+}
+
+func toAuditEntryResponse(entry audit.Entry) audit.EntryResponse {
+	return audit.EntryResponse{
+		Seq:        entry.Seq,
+		Actor:      entry.Actor,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Changes:    entry.Changes,
+		RequestID:  entry.RequestID,
+		TraceID:    entry.TraceID,
+		OccurredAt: entry.OccurredAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+}
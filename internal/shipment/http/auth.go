@@ -0,0 +1,108 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	apikey "shipment-customer-service/internal/domain/apikey"
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/platform/tenant"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// require lets a request through only if it carries an API key or bearer
+// token granting scope and naming a tenant. The principal and its tenant are
+// added to the request context and server span. Routes without a scope are
+// public.
+func (h *Handler) require(scope auth.Scope, next http.Handler) http.Handler {
+	if scope == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := h.authenticate(r)
+		if isAuthError(err) {
+			h.logger.Warn(
+				"auth_failed",
+				slog.String("reason", err.Error()),
+				slog.String("trace_id", telemetry.TraceID(r.Context())),
+			)
+			w.Header().Add("WWW-Authenticate", `ApiKey header="`+auth.APIKeyHeader+`"`)
+			if h.tokens != nil {
+				w.Header().Add("WWW-Authenticate", `Bearer error="invalid_token"`)
+			}
+			writeProblem(w, r, newProblem(http.StatusUnauthorized, domain.CodeUnauthenticated))
+			return
+		}
+		if err != nil {
+			writeProblem(w, r, internalProblem)
+			return
+		}
+
+		trace.SpanFromContext(r.Context()).SetAttributes(
+			attribute.String("enduser.id", principal.ID),
+			attribute.String("auth.method", principal.Method),
+			attribute.String("tenant.id", principal.TenantID),
+		)
+
+		if !principal.HasScope(scope) {
+			h.logger.Warn(
+				"auth_forbidden",
+				slog.String("principal", principal.ID),
+				slog.String("scope", string(scope)),
+				slog.String("trace_id", telemetry.TraceID(r.Context())),
+			)
+			writeProblem(w, r, newProblem(http.StatusForbidden, domain.CodeForbidden))
+			return
+		}
+		// Every shipment belongs to a tenant, so a token without one, e.g.
+		// from an SSO client that lacks the tenant claim, can see nothing.
+		if principal.TenantID == "" {
+			h.logger.Warn(
+				"auth_no_tenant",
+				slog.String("principal", principal.ID),
+				slog.String("trace_id", telemetry.TraceID(r.Context())),
+			)
+			writeProblem(w, r, newProblem(http.StatusForbidden, domain.CodeForbidden))
+			return
+		}
+
+		ctx := tenant.NewContext(auth.NewContext(r.Context(), principal), principal.TenantID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticate prefers a bearer token when the request has one, so SSO users
+// and API key clients can share routes.
+func (h *Handler) authenticate(r *http.Request) (auth.Principal, error) {
+	if token := auth.BearerToken(r.Header.Get("Authorization")); token != "" && h.tokens != nil {
+		return h.tokens.Verify(r.Context(), token)
+	}
+	return h.keys.Authenticate(r.Context(), r.Header.Get(auth.APIKeyHeader))
+}
+
+func isAuthError(err error) bool {
+	return errors.Is(err, apikey.ErrMissingKey) ||
+		errors.Is(err, apikey.ErrInvalidKey) ||
+		errors.Is(err, auth.ErrInvalidToken)
+}
+
+// requireByMethod protects a handler that serves reads and writes, such as
+// the v2 gateway: safe methods need read access, everything else write.
+func (h *Handler) requireByMethod(next http.Handler) http.Handler {
+	read := h.require(auth.ScopeShipmentsRead, next)
+	write := h.require(auth.ScopeShipmentsWrite, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			read.ServeHTTP(w, r)
+		default:
+			write.ServeHTTP(w, r)
+		}
+	})
+}
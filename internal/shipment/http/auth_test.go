@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apikeydomain "shipment-customer-service/internal/domain/apikey"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/tenant"
+	"shipment-customer-service/internal/shipment/apikey"
+)
+
+type fakeKeyRepo struct {
+	apikey.Repository
+	keys map[[sha256.Size]byte]apikeydomain.Key
+}
+
+func (f *fakeKeyRepo) GetAPIKeyByHash(ctx context.Context, hash []byte) (apikeydomain.Key, error) {
+	key, ok := f.keys[[sha256.Size]byte(hash)]
+	if !ok {
+		return apikeydomain.Key{}, sql.ErrNoRows
+	}
+	return key, nil
+}
+
+func (f *fakeKeyRepo) TouchAPIKey(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestRequire(t *testing.T) {
+	repo := &fakeKeyRepo{keys: map[[sha256.Size]byte]apikeydomain.Key{
+		sha256.Sum256([]byte("reader-key")): {ID: "r1", TenantID: "brand-a", Scopes: []auth.Scope{auth.ScopeShipmentsRead}},
+		sha256.Sum256([]byte("admin-key")):  {ID: "a1", TenantID: "brand-a", Scopes: []auth.Scope{auth.ScopeAdmin}},
+		sha256.Sum256([]byte("tenantless")): {ID: "t1", Scopes: []auth.Scope{auth.ScopeAdmin}},
+	}}
+	h := &Handler{keys: apikey.NewService(repo), logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	tests := []struct {
+		name          string
+		scope         auth.Scope
+		key           string
+		bearer        string
+		wantStatus    int
+		wantPrincipal string
+	}{
+		{name: "public route", wantStatus: http.StatusOK},
+		{name: "missing key", scope: auth.ScopeShipmentsRead, wantStatus: http.StatusUnauthorized},
+		{name: "unknown key", scope: auth.ScopeShipmentsRead, key: "nope", wantStatus: http.StatusUnauthorized},
+		{name: "bearer not configured", scope: auth.ScopeShipmentsRead, bearer: "eyJ.eyJ.sig", wantStatus: http.StatusUnauthorized},
+		{name: "missing scope", scope: auth.ScopeShipmentsWrite, key: "reader-key", wantStatus: http.StatusForbidden},
+		{name: "granted scope", scope: auth.ScopeShipmentsRead, key: "reader-key", wantStatus: http.StatusOK, wantPrincipal: "apikey:r1"},
+		{name: "admin grants all", scope: auth.ScopeShipmentsWrite, key: "admin-key", wantStatus: http.StatusOK, wantPrincipal: "apikey:a1"},
+		{name: "missing tenant", scope: auth.ScopeShipmentsRead, key: "tenantless", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPrincipal, gotTenant string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPrincipal = auth.PrincipalID(r.Context())
+				gotTenant, _ = tenant.FromContext(r.Context())
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/shipments/1", nil)
+			if tt.key != "" {
+				request.Header.Set(auth.APIKeyHeader, tt.key)
+			}
+			if tt.bearer != "" {
+				request.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			recorder := httptest.NewRecorder()
+			h.require(tt.scope, next).ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("require() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if gotPrincipal != tt.wantPrincipal {
+				t.Fatalf("require() principal = %q, want %q", gotPrincipal, tt.wantPrincipal)
+			}
+			if tt.wantPrincipal != "" && gotTenant != "brand-a" {
+				t.Fatalf("require() tenant = %q, want brand-a", gotTenant)
+			}
+			if tt.wantStatus == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Fatalf("require() did not send WWW-Authenticate")
+			}
+		})
+	}
+}
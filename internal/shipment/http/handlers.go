@@ -6,131 +6,285 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/health"
+	"shipment-customer-service/internal/platform/ratelimit"
 	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/shipment/apikey"
+	"shipment-customer-service/internal/shipment/auditlog"
 	"shipment-customer-service/internal/shipment/service"
+	"shipment-customer-service/internal/shipment/stream"
+	"shipment-customer-service/internal/shipment/webhook"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Handler struct {
-	service *service.Service
+	service  *service.Service
+	webhooks *webhook.Service
+	keys     *apikey.Service
+	audit    *auditlog.Service
+	// tokens is nil unless bearer token authentication is configured.
+	tokens *auth.Verifier
+	// limiter is nil when rate limiting is off.
+	limiter *ratelimit.Limiter
+	streams *stream.Broker
+	probes  *health.Checker
 	logger  *slog.Logger
 }
 
-func NewHandler(service *service.Service, logger *slog.Logger) http.Handler {
-	h := &Handler{service: service, logger: logger}
+// route is an endpoint and the scope a caller's credentials need for it;
+// an empty scope makes the route public.
+type route struct {
+	pattern string
+	scope   auth.Scope
+	handler http.HandlerFunc
+}
+
+// NewHandler serves the hand-written v1 API. gateway, if not nil, serves the
+// proto-generated v2 API under /api/v2/. Both require an API key from keys or,
+// if tokens is not nil, an SSO bearer token. limiter, if not nil, throttles
+// the authenticated routes, by client address before authentication and per
+// route after it; public ones are left to the proxy. probes decide
+// whether /health reports the service ready.
+func NewHandler(service *service.Service, webhooks *webhook.Service, keys *apikey.Service, auditLog *auditlog.Service, tokens *auth.Verifier, limiter *ratelimit.Limiter, streams *stream.Broker, probes *health.Checker, gateway http.Handler, logger *slog.Logger) http.Handler {
+	h := &Handler{service: service, webhooks: webhooks, keys: keys, audit: auditLog, tokens: tokens, limiter: limiter, streams: streams, probes: probes, logger: logger}
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /health", h.healthCheckHandler)
-	mux.HandleFunc("POST /api/v1/shipments", h.createShipment)
-	mux.HandleFunc("GET /api/v1/shipments/{id}", h.getShipment)
-	return otelhttp.NewHandler(mux, "shipment-http")
+	for _, route := range h.routes() {
+		handler := http.Handler(route.handler)
+		if route.scope != "" {
+			handler = h.limitAddress(h.require(route.scope, h.limit(route.pattern, handler)))
+		}
+		mux.Handle(route.pattern, withRoute(route.pattern, handler))
+	}
+	if gateway != nil {
+		mux.Handle("/api/v2/", withRoute("/api/v2/", h.limitAddress(h.requireByMethod(h.limit("/api/v2/", gateway)))))
+	}
+	return otelhttp.NewHandler(telemetry.RequestIDMiddleware(mux), "shipment-http")
+}
+
+// withRoute labels the request metrics of otelhttp with the route pattern,
+// which keeps their cardinality bounded unlike the request path.
+func withRoute(pattern string, next http.Handler) http.Handler {
+	path := pattern
+	if _, after, ok := strings.Cut(pattern, " "); ok {
+		path = after
+	}
+	route := attribute.String("http.route", path)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if labeler, ok := otelhttp.LabelerFromContext(r.Context()); ok {
+			labeler.Add(route)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routes lists the v1 API. Every entry must be described in openapi.json.
+func (h *Handler) routes() []route {
+	return []route{
+		{"GET /health", "", h.healthCheckHandler},
+		{"GET /api/v1/openapi.json", "", h.openAPI},
+		{"POST /api/v1/shipments", auth.ScopeShipmentsWrite, h.createShipment},
+		{"POST /api/v1/shipments/batch", auth.ScopeShipmentsWrite, h.createShipmentsBatch},
+		{"GET /api/v1/shipments/{id}", auth.ScopeShipmentsRead, h.getShipment},
+		{"GET /api/v1/shipments/stream", auth.ScopeShipmentsRead, h.streamShipments},
+		{"GET /api/v1/shipments/{id}/stream", auth.ScopeShipmentsRead, h.streamShipment},
+		{"POST /api/v1/shipments/{id}/status", auth.ScopeShipmentsWrite, h.transitionShipment},
+		{"POST /api/v1/shipments/{id}/cancel", auth.ScopeShipmentsWrite, h.cancelShipment},
+		{"POST /api/v1/webhooks", auth.ScopeAdmin, h.createWebhook},
+		{"GET /api/v1/webhooks", auth.ScopeAdmin, h.listWebhooks},
+		{"DELETE /api/v1/webhooks/{id}", auth.ScopeAdmin, h.deleteWebhook},
+		{"GET /api/v1/webhooks/{id}/deliveries", auth.ScopeAdmin, h.listWebhookDeliveries},
+		{"GET /api/v1/webhooks/{id}/deliveries/{deliveryId}", auth.ScopeAdmin, h.getWebhookDelivery},
+		{"POST /api/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver", auth.ScopeAdmin, h.redeliverWebhook},
+		{"POST /api/v1/admin/api-keys", auth.ScopeAdmin, h.issueAPIKey},
+		{"GET /api/v1/admin/api-keys", auth.ScopeAdmin, h.listAPIKeys},
+		{"DELETE /api/v1/admin/api-keys/{id}", auth.ScopeAdmin, h.revokeAPIKey},
+		{"GET /api/v1/audit", auth.ScopeAuditRead, h.listAudit},
+		{"GET /api/v1/audit/verify", auth.ScopeAuditRead, h.verifyAudit},
+	}
 }
 
+// healthCheckHandler answers with the readiness checks also served as /readyz
+// on the admin port; their details stay there, off the public API.
 func (h *Handler) healthCheckHandler(w http.ResponseWriter, r *http.Request) {
+	if report := h.probes.Run(r.Context()); !report.Ready() {
+		h.logger.Warn(
+			"health_check_failed",
+			slog.Any("failed_checks", report.Failed()),
+			slog.String("trace_id", telemetry.TraceID(r.Context())),
+		)
+		writeProblem(w, r, newProblem(http.StatusServiceUnavailable, domain.CodeNotReady))
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
 
 func (h *Handler) createShipment(w http.ResponseWriter, r *http.Request) {
 	var request domain.CreateShipmentRequest
-	decoder := json.NewDecoder(r.Body)
-	decoder.DisallowUnknownFields()
-	if err := decoder.Decode(&request); err != nil {
-		writeJSON(w, http.StatusBadRequest, domain.ErrorResponse{Error: "invalid request body"})
-		return
-	}
-	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
-		writeJSON(w, http.StatusBadRequest, domain.ErrorResponse{Error: "invalid request body"})
+	if err := decodeStrict(r, &request); err != nil {
+		writeProblem(w, r, invalidBodyProblem)
 		return
 	}
 
-	shipment, err := h.service.Create(r.Context(), domain.CreateShipmentInput{
-		Route:       request.Route,
-		Price:       request.Price,
-		CustomerIDN: request.Customer.IDN,
-	})
+	shipment, err := h.service.Create(r.Context(), toCreateShipmentInput(request))
 	if err != nil {
-		statusCode, message := mapCreateError(err)
-		writeJSON(w, statusCode, domain.ErrorResponse{Error: message})
+		writeProblem(w, r, mapCreateError(err))
 		return
 	}
 
 	h.logger.Info(
 		"shipment_created",
 		slog.String("shipment_id", shipment.ID),
+		slog.String("principal", auth.PrincipalID(r.Context())),
 		slog.String("trace_id", telemetry.TraceID(r.Context())),
 	)
 
-	writeJSON(w, http.StatusCreated, domain.CreateShipmentResponse{
-		ID:         shipment.ID,
-		Status:     shipment.Status,
-		CustomerID: shipment.CustomerID,
-	})
+	statusCode := http.StatusCreated
+	if shipment.Status == domain.StatusPendingCustomer {
+		// Accepted in degraded mode; the customer is attached later.
+		statusCode = http.StatusAccepted
+	}
+
+	writeJSON(w, statusCode, toCreateShipmentResponse(shipment))
+}
+
+// createShipmentsBatch creates up to domain.MaxBatchSize shipments in one
+// transaction. Validation errors of all items are reported together.
+func (h *Handler) createShipmentsBatch(w http.ResponseWriter, r *http.Request) {
+	var request domain.CreateShipmentsBatchRequest
+	if err := decodeStrict(r, &request); err != nil {
+		writeProblem(w, r, invalidBodyProblem)
+		return
+	}
+
+	inputs := make([]domain.CreateShipmentInput, len(request.Shipments))
+	for i, item := range request.Shipments {
+		inputs[i] = toCreateShipmentInput(item)
+	}
+
+	shipments, err := h.service.CreateBatch(r.Context(), inputs)
+	if err != nil {
+		writeProblem(w, r, mapCreateError(err))
+		return
+	}
+
+	statusCode := http.StatusCreated
+	response := domain.CreateShipmentsBatchResponse{Shipments: make([]domain.CreateShipmentResponse, len(shipments))}
+	for i, shipment := range shipments {
+		if shipment.Status == domain.StatusPendingCustomer {
+			statusCode = http.StatusAccepted
+		}
+		response.Shipments[i] = toCreateShipmentResponse(shipment)
+	}
+
+	h.logger.Info(
+		"shipments_batch_created",
+		slog.Int("count", len(shipments)),
+		slog.String("principal", auth.PrincipalID(r.Context())),
+		slog.String("trace_id", telemetry.TraceID(r.Context())),
+	)
+
+	writeJSON(w, statusCode, response)
 }
 
 func (h *Handler) getShipment(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	shipment, err := h.service.Get(r.Context(), id)
 	if err != nil {
-		statusCode, message := mapGetError(err)
-		writeJSON(w, statusCode, domain.ErrorResponse{Error: message})
+		writeProblem(w, r, mapGetError(err))
 		return
 	}
 
 	h.logger.Info(
 		"shipment_fetched",
 		slog.String("shipment_id", shipment.ID),
+		slog.String("principal", auth.PrincipalID(r.Context())),
+		slog.String("trace_id", telemetry.TraceID(r.Context())),
+	)
+
+	writeJSON(w, http.StatusOK, toGetShipmentResponse(shipment))
+}
+
+func (h *Handler) transitionShipment(w http.ResponseWriter, r *http.Request) {
+	var request domain.TransitionShipmentRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeProblem(w, r, invalidBodyProblem)
+		return
+	}
+
+	shipment, err := h.service.Transition(r.Context(), r.PathValue("id"), request.Status)
+	h.writeUpdated(w, r, shipment, err)
+}
+
+func (h *Handler) cancelShipment(w http.ResponseWriter, r *http.Request) {
+	shipment, err := h.service.Cancel(r.Context(), r.PathValue("id"))
+	h.writeUpdated(w, r, shipment, err)
+}
+
+func (h *Handler) writeUpdated(w http.ResponseWriter, r *http.Request, shipment domain.Shipment, err error) {
+	if err != nil {
+		writeProblem(w, r, mapUpdateError(err))
+		return
+	}
+
+	h.logger.Info(
+		"shipment_status_changed",
+		slog.String("shipment_id", shipment.ID),
+		slog.String("status", shipment.Status),
+		slog.String("principal", auth.PrincipalID(r.Context())),
 		slog.String("trace_id", telemetry.TraceID(r.Context())),
 	)
 
-	writeJSON(w, http.StatusOK, domain.GetShipmentResponse{
+	writeJSON(w, http.StatusOK, toGetShipmentResponse(shipment))
+}
+
+func toCreateShipmentInput(request domain.CreateShipmentRequest) domain.CreateShipmentInput {
+	return domain.CreateShipmentInput{
+		Route:       request.Route,
+		Price:       request.Price,
+		CustomerIDN: request.Customer.IDN,
+	}
+}
+
+func toCreateShipmentResponse(shipment domain.Shipment) domain.CreateShipmentResponse {
+	return domain.CreateShipmentResponse{
+		ID:         shipment.ID,
+		Status:     shipment.Status,
+		CustomerID: shipment.CustomerID,
+	}
+}
+
+func toGetShipmentResponse(shipment domain.Shipment) domain.GetShipmentResponse {
+	return domain.GetShipmentResponse{
 		ID:         shipment.ID,
 		Route:      shipment.Route,
 		Price:      shipment.Price,
 		Status:     shipment.Status,
 		CustomerID: shipment.CustomerID,
 		CreatedAt:  shipment.CreatedAt.UTC().Format(time.RFC3339),
-	})
-}
-
-func mapCreateError(err error) (int, string) {
-	switch {
-	case errors.Is(err, domain.ErrInvalidRoute):
-		return http.StatusBadRequest, err.Error()
-	case errors.Is(err, domain.ErrInvalidPrice):
-		return http.StatusBadRequest, err.Error()
-	case errors.Is(err, domain.ErrInvalidIDN):
-		return http.StatusBadRequest, err.Error()
-	}
-
-	if grpcStatus, ok := status.FromError(err); ok {
-		switch grpcStatus.Code() {
-		case codes.InvalidArgument:
-			return http.StatusBadRequest, grpcStatus.Message()
-		case codes.Unavailable:
-			return http.StatusServiceUnavailable, "customer service unavailable"
-		default:
-			return http.StatusBadGateway, grpcStatus.Message()
-		}
 	}
-
-	return http.StatusInternalServerError, "internal error"
 }
 
-func mapGetError(err error) (int, string) {
-	switch {
-	case errors.Is(err, domain.ErrInvalidShipmentID):
-		return http.StatusBadRequest, err.Error()
-	case errors.Is(err, domain.ErrNotFound):
-		return http.StatusNotFound, err.Error()
-	default:
-		return http.StatusInternalServerError, "internal error"
+// decodeStrict decodes exactly one JSON value without unknown fields.
+func decodeStrict(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return errors.New("unexpected data after JSON body")
 	}
+	return nil
 }
 
 func writeJSON(w http.ResponseWriter, statusCode int, payload any) {
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestWithRouteLabelsMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	mux := http.NewServeMux()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	mux.Handle("GET /api/v1/shipments/{id}", withRoute("GET /api/v1/shipments/{id}", next))
+	mux.Handle("/api/v2/", withRoute("/api/v2/", next))
+	handler := otelhttp.NewHandler(mux, "shipment-http", otelhttp.WithMeterProvider(provider))
+
+	for _, path := range []string{"/api/v1/shipments/1", "/api/v1/shipments/2", "/api/v2/shipments"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	got := map[string]uint64{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			histogram, ok := m.Data.(metricdata.Histogram[float64])
+			if m.Name != "http.server.request.duration" || !ok {
+				continue
+			}
+			for _, point := range histogram.DataPoints {
+				route, _ := point.Attributes.Value(attribute.Key("http.route"))
+				got[route.AsString()] += point.Count
+			}
+		}
+	}
+
+	want := map[string]uint64{"/api/v1/shipments/{id}": 2, "/api/v2/": 1}
+	if len(got) != len(want) || got["/api/v1/shipments/{id}"] != 2 || got["/api/v2/"] != 1 {
+		t.Fatalf("requests by route = %v, want %v", got, want)
+	}
+}
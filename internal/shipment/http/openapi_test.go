@@ -0,0 +1,154 @@
+package http
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	apikey "shipment-customer-service/internal/domain/apikey"
+	domain "shipment-customer-service/internal/domain/shipment"
+	webhook "shipment-customer-service/internal/domain/webhook"
+	"shipment-customer-service/internal/platform/audit"
+)
+
+type openAPIDocument struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+			Required   []string                   `json:"required"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	Scope    string            `json:"x-scope"`
+	Security *[]map[string]any `json:"security"`
+}
+
+// apiTypes are the request and response bodies of the v1 API; each needs a
+// schema of the same name in openapi.json.
+var apiTypes = []any{
+	domain.CreateShipmentRequest{},
+	domain.CreateShipmentCustomer{},
+	domain.CreateShipmentResponse{},
+	domain.CreateShipmentsBatchRequest{},
+	domain.CreateShipmentsBatchResponse{},
+	domain.TransitionShipmentRequest{},
+	domain.GetShipmentResponse{},
+	domain.Problem{},
+	domain.FieldError{},
+	domain.Event{},
+	domain.EventData{},
+	webhook.CreateSubscriptionRequest{},
+	webhook.SubscriptionResponse{},
+	webhook.DeliveryResponse{},
+	webhook.AttemptResponse{},
+	apikey.IssueKeyRequest{},
+	apikey.KeyResponse{},
+	audit.EntryResponse{},
+	audit.Change{},
+	audit.EntriesResponse{},
+	audit.ChainResponse{},
+}
+
+func loadOpenAPI(t *testing.T) openAPIDocument {
+	t.Helper()
+	var doc openAPIDocument
+	if err := json.Unmarshal(openAPISpec, &doc); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Fatalf("openapi = %q, want 3.1.0", doc.OpenAPI)
+	}
+	return doc
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	doc := loadOpenAPI(t)
+
+	documented := map[string]openAPIOperation{}
+	for path, operations := range doc.Paths {
+		for method, raw := range operations {
+			if method == "parameters" {
+				continue
+			}
+			var operation openAPIOperation
+			if err := json.Unmarshal(raw, &operation); err != nil {
+				t.Fatalf("operation %s %s: %v", method, path, err)
+			}
+			documented[strings.ToUpper(method)+" "+path] = operation
+		}
+	}
+
+	for _, route := range (&Handler{}).routes() {
+		operation, ok := documented[route.pattern]
+		if !ok {
+			t.Errorf("route %q is missing from openapi.json", route.pattern)
+			continue
+		}
+		if operation.Scope != string(route.scope) {
+			t.Errorf("openapi.json documents scope %q for %q, want %q", operation.Scope, route.pattern, route.scope)
+		}
+		if public := operation.Security != nil && len(*operation.Security) == 0; public != (route.scope == "") {
+			t.Errorf("openapi.json security of %q does not match scope %q", route.pattern, route.scope)
+		}
+		delete(documented, route.pattern)
+	}
+	for operation := range documented {
+		t.Errorf("openapi.json documents %q, which has no handler", operation)
+	}
+}
+
+func TestOpenAPICoversFields(t *testing.T) {
+	doc := loadOpenAPI(t)
+
+	for _, value := range apiTypes {
+		typ := reflect.TypeOf(value)
+		t.Run(typ.Name(), func(t *testing.T) {
+			schema, ok := doc.Components.Schemas[typ.Name()]
+			if !ok {
+				t.Fatalf("schema %s is missing from openapi.json", typ.Name())
+			}
+
+			fields := jsonFields(typ)
+			for _, field := range fields {
+				if _, ok := schema.Properties[field]; !ok {
+					t.Errorf("field %s.%s is missing from openapi.json", typ.Name(), field)
+				}
+			}
+			for property := range schema.Properties {
+				if !slices.Contains(fields, property) {
+					t.Errorf("openapi.json documents %s.%s, which the type does not have", typ.Name(), property)
+				}
+			}
+			for _, required := range schema.Required {
+				if !slices.Contains(fields, required) {
+					t.Errorf("openapi.json requires unknown field %s.%s", typ.Name(), required)
+				}
+			}
+		})
+	}
+}
+
+func jsonFields(typ reflect.Type) []string {
+	var fields []string
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
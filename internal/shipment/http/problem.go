@@ -0,0 +1,185 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	apikey "shipment-customer-service/internal/domain/apikey"
+	domain "shipment-customer-service/internal/domain/shipment"
+	webhook "shipment-customer-service/internal/domain/webhook"
+	"shipment-customer-service/internal/platform/i18n"
+	"shipment-customer-service/internal/platform/telemetry"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// problem is an error response before it is rendered for a request. Its
+// texts are looked up by code in the client's language when it is written.
+type problem struct {
+	status int
+	code   string
+	errors []domain.FieldError
+	// retryAfter, if set, is sent as Retry-After.
+	retryAfter time.Duration
+}
+
+func newProblem(statusCode int, code string) problem {
+	return problem{status: statusCode, code: code}
+}
+
+// fieldProblem reports a single invalid request field as a validation
+// failure.
+func fieldProblem(pointer, code string) problem {
+	return problem{
+		status: http.StatusBadRequest,
+		code:   domain.CodeValidationFailed,
+		errors: []domain.FieldError{{Pointer: pointer, Code: code}},
+	}
+}
+
+// validationProblem lists every invalid field of a request.
+func validationProblem(err *domain.ValidationError) problem {
+	return problem{
+		status: http.StatusBadRequest,
+		code:   domain.CodeValidationFailed,
+		errors: slices.Clone(err.Fields),
+	}
+}
+
+var (
+	invalidBodyProblem = newProblem(http.StatusBadRequest, domain.CodeInvalidRequestBody)
+	internalProblem    = newProblem(http.StatusInternalServerError, domain.CodeInternal)
+)
+
+// writeProblem renders p in the language negotiated from Accept-Language.
+// Field errors carry their own message and the detail lists them all; other
+// problems get the detail of their code.
+func writeProblem(w http.ResponseWriter, r *http.Request, p problem) {
+	lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+
+	details := make([]string, len(p.errors))
+	for i := range p.errors {
+		p.errors[i].Detail = domain.ProblemTitle(p.errors[i].Code, lang)
+		details[i] = p.errors[i].Pointer + ": " + p.errors[i].Detail
+	}
+	detail := strings.Join(details, "; ")
+	if len(p.errors) == 0 {
+		detail = domain.ProblemDetail(p.code, lang)
+	}
+
+	if p.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(p.retryAfter)))
+	}
+	w.Header().Set("Content-Type", domain.ProblemContentType)
+	w.Header().Set("Content-Language", string(lang))
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(p.status)
+	_ = json.NewEncoder(w).Encode(domain.Problem{
+		Type:     domain.ProblemTypePrefix + p.code,
+		Title:    domain.ProblemTitle(p.code, lang),
+		Status:   p.status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     p.code,
+		TraceID:  telemetry.TraceID(r.Context()),
+		Errors:   p.errors,
+	})
+}
+
+func mapCreateError(err error) problem {
+	var validation *domain.ValidationError
+	if errors.As(err, &validation) {
+		return validationProblem(validation)
+	}
+	if errors.Is(err, domain.ErrForbidden) {
+		return newProblem(http.StatusForbidden, domain.CodeForbidden)
+	}
+	if errors.Is(err, domain.ErrQuotaExceeded) {
+		p := newProblem(http.StatusTooManyRequests, domain.CodeQuotaExceeded)
+		p.retryAfter = domain.QuotaResetIn(time.Now())
+		return p
+	}
+
+	// Upstream messages are not passed on: they are not part of this API's
+	// contract, are not localised and may leak internals.
+	if grpcStatus, ok := status.FromError(err); ok {
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			return newProblem(http.StatusBadRequest, domain.CodeCustomerRejected)
+		case codes.Unavailable:
+			return newProblem(http.StatusServiceUnavailable, domain.CodeCustomerServiceUnavailable)
+		case codes.DeadlineExceeded:
+			return newProblem(http.StatusGatewayTimeout, domain.CodeCustomerServiceTimeout)
+		default:
+			return newProblem(http.StatusBadGateway, domain.CodeCustomerServiceError)
+		}
+	}
+
+	return internalProblem
+}
+
+func mapGetError(err error) problem {
+	switch {
+	case errors.Is(err, domain.ErrInvalidShipmentID):
+		return newProblem(http.StatusBadRequest, domain.CodeInvalidShipmentID)
+	case errors.Is(err, domain.ErrNotFound):
+		return newProblem(http.StatusNotFound, domain.CodeShipmentNotFound)
+	case errors.Is(err, domain.ErrForbidden):
+		return newProblem(http.StatusForbidden, domain.CodeForbidden)
+	default:
+		return internalProblem
+	}
+}
+
+func mapUpdateError(err error) problem {
+	switch {
+	case errors.Is(err, domain.ErrInvalidStatus):
+		return fieldProblem("/status", domain.CodeInvalidStatus)
+	case errors.Is(err, domain.ErrInvalidTransition):
+		return newProblem(http.StatusConflict, domain.CodeTransitionNotAllowed)
+	case errors.Is(err, domain.ErrStatusConflict):
+		return newProblem(http.StatusConflict, domain.CodeStatusConflict)
+	default:
+		return mapGetError(err)
+	}
+}
+
+func mapWebhookError(err error) problem {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidURL):
+		return fieldProblem("/url", domain.CodeInvalidWebhookURL)
+	case errors.Is(err, webhook.ErrInvalidEventType):
+		return fieldProblem("/event_types", domain.CodeInvalidEventType)
+	case errors.Is(err, webhook.ErrInvalidSecret):
+		return fieldProblem("/secret", domain.CodeInvalidWebhookSecret)
+	case errors.Is(err, webhook.ErrInvalidID):
+		return newProblem(http.StatusBadRequest, domain.CodeInvalidID)
+	case errors.Is(err, webhook.ErrSubscriptionNotFound):
+		return newProblem(http.StatusNotFound, domain.CodeSubscriptionNotFound)
+	case errors.Is(err, webhook.ErrDeliveryNotFound):
+		return newProblem(http.StatusNotFound, domain.CodeDeliveryNotFound)
+	default:
+		return internalProblem
+	}
+}
+
+func mapAPIKeyError(err error) problem {
+	switch {
+	case errors.Is(err, apikey.ErrInvalidName):
+		return fieldProblem("/name", domain.CodeInvalidAPIKeyName)
+	case errors.Is(err, apikey.ErrInvalidScope):
+		return fieldProblem("/scopes", domain.CodeInvalidScope)
+	case errors.Is(err, apikey.ErrInvalidID):
+		return newProblem(http.StatusBadRequest, domain.CodeInvalidID)
+	case errors.Is(err, apikey.ErrKeyNotFound):
+		return newProblem(http.StatusNotFound, domain.CodeAPIKeyNotFound)
+	default:
+		return internalProblem
+	}
+}
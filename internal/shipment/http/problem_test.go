@@ -0,0 +1,194 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/i18n"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMapCreateError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantPointer string
+	}{
+		{name: "invalid route", err: domain.CreateShipmentInput{Price: 1, CustomerIDN: "990101123456"}.Validate(), wantStatus: http.StatusBadRequest, wantCode: domain.CodeValidationFailed, wantPointer: "/route"},
+		{name: "invalid idn", err: domain.CreateShipmentInput{Route: "A-B", Price: 1}.Validate(), wantStatus: http.StatusBadRequest, wantCode: domain.CodeValidationFailed, wantPointer: "/customer/idn"},
+		{name: "customer rejected", err: status.Error(codes.InvalidArgument, "idn must be 12 digits"), wantStatus: http.StatusBadRequest, wantCode: domain.CodeCustomerRejected},
+		{name: "customer unavailable", err: status.Error(codes.Unavailable, "connection refused"), wantStatus: http.StatusServiceUnavailable, wantCode: domain.CodeCustomerServiceUnavailable},
+		{name: "customer timeout", err: status.Error(codes.DeadlineExceeded, "deadline"), wantStatus: http.StatusGatewayTimeout, wantCode: domain.CodeCustomerServiceTimeout},
+		{name: "customer failure", err: status.Error(codes.Internal, "pq: relation missing"), wantStatus: http.StatusBadGateway, wantCode: domain.CodeCustomerServiceError},
+		{name: "not own customer", err: domain.ErrForbidden, wantStatus: http.StatusForbidden, wantCode: domain.CodeForbidden},
+		{name: "quota exceeded", err: domain.ErrQuotaExceeded, wantStatus: http.StatusTooManyRequests, wantCode: domain.CodeQuotaExceeded},
+		{name: "unknown", err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantCode: domain.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapCreateError(tt.err)
+			if got.status != tt.wantStatus || got.code != tt.wantCode {
+				t.Fatalf("mapCreateError() = %d %s, want %d %s", got.status, got.code, tt.wantStatus, tt.wantCode)
+			}
+			if tt.wantPointer != "" && (len(got.errors) != 1 || got.errors[0].Pointer != tt.wantPointer) {
+				t.Fatalf("mapCreateError() errors = %+v, want pointer %s", got.errors, tt.wantPointer)
+			}
+		})
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantLanguage   i18n.Language
+	}{
+		{name: "default", wantLanguage: i18n.English},
+		{name: "russian", acceptLanguage: "ru-RU,ru;q=0.9", wantLanguage: i18n.Russian},
+		{name: "kazakh", acceptLanguage: "kk, ru;q=0.5", wantLanguage: i18n.Kazakh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/shipments", nil)
+			request.Header.Set("Accept-Language", tt.acceptLanguage)
+			recorder := httptest.NewRecorder()
+
+			invalid := domain.CreateShipmentInput{Route: "", Price: 0, CustomerIDN: "990101123456"}.Validate()
+			writeProblem(recorder, request, mapCreateError(invalid))
+
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("writeProblem() status = %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+			if got := recorder.Header().Get("Content-Type"); got != domain.ProblemContentType {
+				t.Fatalf("writeProblem() content type = %q, want %q", got, domain.ProblemContentType)
+			}
+			if got := recorder.Header().Get("Content-Language"); got != string(tt.wantLanguage) {
+				t.Fatalf("writeProblem() content language = %q, want %q", got, tt.wantLanguage)
+			}
+
+			var got domain.Problem
+			if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+				t.Fatalf("writeProblem() body is not JSON: %v", err)
+			}
+			routeMessage := domain.ProblemTitle(domain.CodeInvalidRoute, tt.wantLanguage)
+			priceMessage := domain.ProblemTitle(domain.CodeInvalidPrice, tt.wantLanguage)
+			want := domain.Problem{
+				Type:     domain.ProblemTypePrefix + domain.CodeValidationFailed,
+				Title:    domain.ProblemTitle(domain.CodeValidationFailed, tt.wantLanguage),
+				Status:   http.StatusBadRequest,
+				Detail:   "/route: " + routeMessage + "; /price: " + priceMessage,
+				Instance: "/api/v1/shipments",
+				Code:     domain.CodeValidationFailed,
+			}
+			if got.Type != want.Type || got.Title != want.Title || got.Status != want.Status ||
+				got.Detail != want.Detail || got.Instance != want.Instance || got.Code != want.Code {
+				t.Fatalf("writeProblem() = %+v, want %+v", got, want)
+			}
+			wantErrors := []domain.FieldError{
+				{Pointer: "/route", Code: domain.CodeInvalidRoute, Detail: routeMessage},
+				{Pointer: "/price", Code: domain.CodeInvalidPrice, Detail: priceMessage},
+			}
+			if !slices.Equal(got.Errors, wantErrors) {
+				t.Fatalf("writeProblem() errors = %+v, want %+v", got.Errors, wantErrors)
+			}
+		})
+	}
+}
+
+func TestWriteProblemDetail(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantLanguage   i18n.Language
+	}{
+		{name: "default", wantLanguage: i18n.English},
+		{name: "russian", acceptLanguage: "ru", wantLanguage: i18n.Russian},
+		{name: "kazakh", acceptLanguage: "kk", wantLanguage: i18n.Kazakh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/shipments/x", nil)
+			request.Header.Set("Accept-Language", tt.acceptLanguage)
+			recorder := httptest.NewRecorder()
+			writeProblem(recorder, request, newProblem(http.StatusNotFound, domain.CodeShipmentNotFound))
+
+			var got domain.Problem
+			if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+				t.Fatalf("writeProblem() body is not JSON: %v", err)
+			}
+			want := domain.ProblemDetails.Message(domain.CodeShipmentNotFound, tt.wantLanguage)
+			if got.Detail != want || want == "" {
+				t.Fatalf("writeProblem() detail = %q, want %q", got.Detail, want)
+			}
+		})
+	}
+}
+
+// TestEveryProblemHasDetail walks the handlers so that a new newProblem call
+// with a code lacking a detail is caught.
+func TestEveryProblemHasDetail(t *testing.T) {
+	codes := problemCodes(t)
+	packages, err := parser.ParseDir(token.NewFileSet(), ".", nil, 0)
+	if err != nil {
+		t.Fatalf("parse package: %v", err)
+	}
+	for _, pkg := range packages {
+		ast.Inspect(pkg, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 {
+				return true
+			}
+			if fn, ok := call.Fun.(*ast.Ident); !ok || fn.Name != "newProblem" {
+				return true
+			}
+			if code, ok := call.Args[1].(*ast.SelectorExpr); ok {
+				if _, ok := domain.ProblemDetails[codes[code.Sel.Name]]; !ok {
+					t.Errorf("domain.%s is used by newProblem but has no entry in ProblemDetails", code.Sel.Name)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// problemCodes maps the names of the problem code constants to their values.
+func problemCodes(t *testing.T) map[string]string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "../../domain/shipment/problem_codes.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse problem_codes.go: %v", err)
+	}
+	codes := make(map[string]string)
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if i >= len(spec.Values) {
+				break
+			}
+			if literal, ok := spec.Values[i].(*ast.BasicLit); ok {
+				codes[name.Name], _ = strconv.Unquote(literal.Value)
+			}
+		}
+		return true
+	})
+	return codes
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/ratelimit"
+	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+// limit throttles requests to the route with the given pattern. It runs after
+// require, so buckets keyed by client or tenant see the caller; on public
+// routes those fall back to the client address.
+func (h *Handler) limit(pattern string, next http.Handler) http.Handler {
+	if h.limiter == nil {
+		return next
+	}
+	policy, ok := h.limiter.Policy(pattern)
+	if !ok {
+		return next
+	}
+	return h.throttle(pattern, policy, func(r *http.Request) string {
+		return h.rateLimitKey(r, policy.By)
+	}, next)
+}
+
+// limitAddress throttles requests by client address before require sees
+// them, so that callers with missing or invalid credentials, which no client
+// or tenant bucket holds back, cannot run up key lookups without bound.
+func (h *Handler) limitAddress(next http.Handler) http.Handler {
+	if h.limiter == nil {
+		return next
+	}
+	policy, ok := h.limiter.Policy(ratelimit.AuthRoute)
+	if !ok {
+		return next
+	}
+	return h.throttle(ratelimit.AuthRoute, policy, func(r *http.Request) string {
+		return "ip:" + h.limiter.ClientIP(r)
+	}, next)
+}
+
+func (h *Handler) throttle(route string, policy ratelimit.Policy, keyOf func(*http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := keyOf(r)
+		decision := h.limiter.Allow(route, key)
+		setRateLimitHeaders(w, policy, decision)
+		if !decision.Allowed {
+			h.logger.Warn(
+				"rate_limited",
+				slog.String("route", route),
+				slog.String("key", key),
+				slog.String("principal", auth.PrincipalID(r.Context())),
+				slog.String("trace_id", telemetry.TraceID(r.Context())),
+			)
+			p := newProblem(http.StatusTooManyRequests, domain.CodeRateLimited)
+			p.retryAfter = decision.RetryAfter
+			writeProblem(w, r, p)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *Handler) rateLimitKey(r *http.Request, by ratelimit.KeyBy) string {
+	switch by {
+	case ratelimit.ByClient:
+		if id := auth.PrincipalID(r.Context()); id != "" {
+			return "client:" + id
+		}
+	case ratelimit.ByTenant:
+		if id, err := tenant.ID(r.Context()); err == nil {
+			return "tenant:" + id
+		}
+	}
+	return "ip:" + h.limiter.ClientIP(r)
+}
+
+// setRateLimitHeaders describes the caller's bucket in the fields of the
+// IETF RateLimit header draft: the policy as a quota per window, what is left
+// of it and when it is whole again, in seconds.
+func setRateLimitHeaders(w http.ResponseWriter, policy ratelimit.Policy, decision ratelimit.Decision) {
+	w.Header().Set("RateLimit-Policy", strconv.Itoa(policy.Burst)+";w="+strconv.Itoa(ceilSeconds(policy.Window())))
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+}
+
+// ceilSeconds rounds d up to whole seconds, so that a client waiting that
+// long is not turned away again.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
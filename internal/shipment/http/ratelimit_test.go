@@ -0,0 +1,99 @@
+package http
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/ratelimit"
+)
+
+func TestLimit(t *testing.T) {
+	h := &Handler{
+		limiter: ratelimit.New(ratelimit.Config{Policies: map[string]ratelimit.Policy{
+			"POST /api/v1/shipments": {Rate: 0.5, Burst: 1, By: ratelimit.ByClient},
+		}}),
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := h.limit("POST /api/v1/shipments", next)
+
+	tests := []struct {
+		name           string
+		principal      string
+		wantStatus     int
+		wantRemaining  string
+		wantRetryAfter string
+	}{
+		{name: "first request", principal: "apikey:a1", wantStatus: http.StatusOK, wantRemaining: "0"},
+		{name: "bucket empty", principal: "apikey:a1", wantStatus: http.StatusTooManyRequests, wantRemaining: "0", wantRetryAfter: "2"},
+		{name: "other client", principal: "apikey:a2", wantStatus: http.StatusOK, wantRemaining: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/api/v1/shipments", nil)
+			request = request.WithContext(auth.NewContext(request.Context(), auth.Principal{ID: tt.principal}))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("limit() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if got := recorder.Header().Get("RateLimit-Remaining"); got != tt.wantRemaining {
+				t.Fatalf("RateLimit-Remaining = %q, want %q", got, tt.wantRemaining)
+			}
+			if got := recorder.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if got := recorder.Header().Get("RateLimit-Policy"); got != "1;w=2" {
+				t.Fatalf("RateLimit-Policy = %q, want 1;w=2", got)
+			}
+		})
+	}
+}
+
+func TestLimitAddress(t *testing.T) {
+	h := &Handler{
+		limiter: ratelimit.New(ratelimit.Config{Policies: map[string]ratelimit.Policy{
+			ratelimit.AuthRoute: {Rate: 0.5, Burst: 1, By: ratelimit.ByIP},
+		}}),
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	reached := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	handler := h.limitAddress(next)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		wantStatus int
+		wantReach  int
+	}{
+		{name: "first failure", remoteAddr: "192.0.2.1:1000", wantStatus: http.StatusUnauthorized, wantReach: 1},
+		{name: "same address", remoteAddr: "192.0.2.1:1001", wantStatus: http.StatusTooManyRequests, wantReach: 1},
+		{name: "other address", remoteAddr: "192.0.2.2:1000", wantStatus: http.StatusUnauthorized, wantReach: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/v1/shipments", nil)
+			request.RemoteAddr = tt.remoteAddr
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("limitAddress() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if reached != tt.wantReach {
+				t.Fatalf("limitAddress() passed %d requests on, want %d", reached, tt.wantReach)
+			}
+		})
+	}
+}
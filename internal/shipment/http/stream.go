@@ -0,0 +1,144 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/tenant"
+	"shipment-customer-service/internal/shipment/stream"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+func (h *Handler) streamShipment(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := h.service.Get(r.Context(), id); err != nil {
+		writeProblem(w, r, mapGetError(err))
+		return
+	}
+
+	h.serveEvents(w, r, stream.Filter{ShipmentID: id})
+}
+
+func (h *Handler) streamShipments(w http.ResponseWriter, r *http.Request) {
+	customerID := r.URL.Query().Get("customer")
+	if customerID != "" {
+		if _, err := uuid.Parse(customerID); err != nil {
+			writeProblem(w, r, newProblem(http.StatusBadRequest, domain.CodeInvalidCustomerID))
+			return
+		}
+	}
+	customerID, err := h.service.AuthorizeFeed(r.Context(), customerID)
+	if err != nil {
+		writeProblem(w, r, mapGetError(err))
+		return
+	}
+
+	h.serveEvents(w, r, stream.Filter{CustomerID: customerID})
+}
+
+// serveEvents streams matching shipment events as Server-Sent Events. The
+// event id is the outbox sequence number, so a client reconnecting with
+// Last-Event-ID first receives everything it missed.
+func (h *Handler) serveEvents(w http.ResponseWriter, r *http.Request, filter stream.Filter) {
+	filter.TenantID, _ = tenant.FromContext(r.Context())
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeProblem(w, r, newProblem(http.StatusInternalServerError, domain.CodeStreamingUnsupported))
+		return
+	}
+
+	var lastSeq int64
+	resume := r.Header.Get("Last-Event-ID")
+	if resume != "" {
+		seq, err := strconv.ParseInt(resume, 10, 64)
+		if err != nil || seq < 0 {
+			writeProblem(w, r, newProblem(http.StatusBadRequest, domain.CodeInvalidLastEventID))
+			return
+		}
+		lastSeq = seq
+	}
+
+	// Subscribe before replaying so nothing committed in between is lost;
+	// duplicates are skipped by sequence number below.
+	sub := h.streams.Subscribe(filter)
+	defer sub.Close()
+
+	// Headers go out with the first event, so a replay that fails before it
+	// can still be answered with a problem.
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "retry: 3000\n\n")
+	}
+
+	if resume != "" {
+		var writeErr error
+		seq, err := h.streams.Replay(r.Context(), lastSeq, filter, func(event domain.Event) error {
+			start()
+			writeErr = writeEvent(w, event)
+			return writeErr
+		})
+		if writeErr != nil {
+			return
+		}
+		if err != nil {
+			if !started {
+				writeProblem(w, r, internalProblem)
+			}
+			// Otherwise the client reconnects with the last event ID it got.
+			return
+		}
+		lastSeq = seq
+	}
+	start()
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if event.Seq <= lastSeq {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			lastSeq = event.Seq
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event domain.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+	return err
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	webhook "shipment-customer-service/internal/domain/webhook"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/telemetry"
+)
+
+func (h *Handler) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var request webhook.CreateSubscriptionRequest
+	if err := decodeStrict(r, &request); err != nil {
+		writeProblem(w, r, invalidBodyProblem)
+		return
+	}
+
+	subscription, err := h.webhooks.CreateSubscription(r.Context(), webhook.CreateSubscriptionInput{
+		URL:        request.URL,
+		Secret:     request.Secret,
+		EventTypes: request.EventTypes,
+	})
+	if err != nil {
+		writeProblem(w, r, mapWebhookError(err))
+		return
+	}
+
+	h.logger.Info(
+		"webhook_created",
+		slog.String("webhook_id", subscription.ID),
+		slog.String("principal", auth.PrincipalID(r.Context())),
+		slog.String("trace_id", telemetry.TraceID(r.Context())),
+	)
+
+	response := toSubscriptionResponse(subscription)
+	response.Secret = subscription.Secret
+	writeJSON(w, http.StatusCreated, response)
+}
+
+func (h *Handler) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhooks.ListSubscriptions(r.Context())
+	if err != nil {
+		writeProblem(w, r, mapWebhookError(err))
+		return
+	}
+
+	response := make([]webhook.SubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, toSubscriptionResponse(subscription))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.webhooks.DeleteSubscription(r.Context(), r.PathValue("id")); err != nil {
+		writeProblem(w, r, mapWebhookError(err))
+		return
+	}
+
+	h.logger.Info(
+		"webhook_deleted",
+		slog.String("webhook_id", r.PathValue("id")),
+		slog.String("principal", auth.PrincipalID(r.Context())),
+		slog.String("trace_id", telemetry.TraceID(r.Context())),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) listWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.webhooks.ListDeliveries(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeProblem(w, r, mapWebhookError(err))
+		return
+	}
+
+	response := make([]webhook.DeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, toDeliveryResponse(delivery, nil))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) getWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, attempts, err := h.webhooks.GetDelivery(r.Context(), r.PathValue("id"), r.PathValue("deliveryId"))
+	if err != nil {
+		writeProblem(w, r, mapWebhookError(err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toDeliveryResponse(delivery, attempts))
+}
+
+func (h *Handler) redeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.webhooks.Redeliver(r.Context(), r.PathValue("id"), r.PathValue("deliveryId"))
+	if err != nil {
+		writeProblem(w, r, mapWebhookError(err))
+		return
+	}
+
+	h.logger.Info(
+		"webhook_redelivery_queued",
+		slog.String("delivery_id", delivery.ID),
+		slog.String("principal", auth.PrincipalID(r.Context())),
+		slog.String("trace_id", telemetry.TraceID(r.Context())),
+	)
+
+	writeJSON(w, http.StatusAccepted, toDeliveryResponse(delivery, nil))
+}
+
+func toSubscriptionResponse(subscription webhook.Subscription) webhook.SubscriptionResponse {
+	return webhook.SubscriptionResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toDeliveryResponse(delivery webhook.Delivery, attempts []webhook.Attempt) webhook.DeliveryResponse {
+	response := webhook.DeliveryResponse{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if delivery.Status == webhook.DeliveryPending {
+		response.NextAttemptAt = delivery.NextAttemptAt.UTC().Format(time.RFC3339)
+	}
+	if delivery.DeliveredAt != nil {
+		response.DeliveredAt = delivery.DeliveredAt.UTC().Format(time.RFC3339)
+	}
+	for _, attempt := range attempts {
+		response.AttemptLog = append(response.AttemptLog, webhook.AttemptResponse{
+			Number:      attempt.Number,
+			StatusCode:  attempt.StatusCode,
+			Error:       attempt.Error,
+			DurationMS:  attempt.Duration.Milliseconds(),
+			AttemptedAt: attempt.AttemptedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return response
+}
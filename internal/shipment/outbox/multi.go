@@ -0,0 +1,22 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+)
+
+// MultiPublisher publishes every event to all of its publishers. If any of
+// them fails the event is retried for all, so each must be idempotent.
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event domain.Event) error {
+	var errs []error
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,96 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+)
+
+// Publisher delivers shipment events to the outside world. Delivery is
+// at-least-once: the same event may be published more than once, so
+// implementations and their consumers must tolerate duplicates by Event.ID.
+type Publisher interface {
+	Publish(ctx context.Context, event domain.Event) error
+}
+
+type Store interface {
+	ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]domain.Event, error)
+	MarkOutboxPublished(ctx context.Context, seq int64) error
+	MarkOutboxFailed(ctx context.Context, seq int64, cause string, retryAfter time.Duration) error
+}
+
+// Relay polls the outbox table and hands committed events to a Publisher.
+type Relay struct {
+	store      Store
+	publisher  Publisher
+	logger     *slog.Logger
+	interval   time.Duration
+	batchSize  int
+	lease      time.Duration
+	retryAfter time.Duration
+}
+
+func NewRelay(store Store, publisher Publisher, logger *slog.Logger) *Relay {
+	return &Relay{
+		store:      store,
+		publisher:  publisher,
+		logger:     logger,
+		interval:   time.Second,
+		batchSize:  100,
+		lease:      30 * time.Second,
+		retryAfter: 10 * time.Second,
+	}
+}
+
+// Run relays events until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if r.flush(ctx) == r.batchSize {
+			// A full batch means more events are likely waiting.
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) flush(ctx context.Context) int {
+	events, err := r.store.ClaimOutboxEvents(ctx, r.batchSize, r.lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("outbox_claim_failed", slog.String("error", err.Error()))
+		}
+		return 0
+	}
+
+	for _, event := range events {
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			r.logger.Warn(
+				"outbox_publish_failed",
+				slog.Int64("seq", event.Seq),
+				slog.String("event_type", event.Type),
+				slog.String("error", err.Error()),
+			)
+			if err := r.store.MarkOutboxFailed(ctx, event.Seq, err.Error(), r.retryAfter); err != nil {
+				r.logger.Error("outbox_mark_failed", slog.Int64("seq", event.Seq), slog.String("error", err.Error()))
+			}
+			continue
+		}
+
+		if err := r.store.MarkOutboxPublished(ctx, event.Seq); err != nil {
+			// The lease will expire and the event is published again.
+			r.logger.Error("outbox_mark_published_failed", slog.Int64("seq", event.Seq), slog.String("error", err.Error()))
+		}
+	}
+
+	return len(events)
+}
@@ -0,0 +1,88 @@
+package reconcile
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	customerpb "shipment-customer-service/api/proto"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+type ErasureStore interface {
+	ErasePendingCustomerIDN(ctx context.Context, idn string) (int64, error)
+	CustomerChangeCursor(ctx context.Context) (int64, error)
+	SaveCustomerChangeCursor(ctx context.Context, seq int64) error
+}
+
+// ErasureFollower applies customer erasures to pending shipments, which hold
+// the IDN of a customer not resolved yet. It follows the ERASED entries of the
+// customer change stream of all tenants from a cursor kept in the database,
+// so erasures made while shipment-service was down are applied once it is
+// back. The reconciler then cancels the shipments.
+type ErasureFollower struct {
+	store          ErasureStore
+	client         customerpb.CustomerServiceClient
+	logger         *slog.Logger
+	reconnectDelay time.Duration
+}
+
+func NewErasureFollower(store ErasureStore, client customerpb.CustomerServiceClient, logger *slog.Logger) *ErasureFollower {
+	return &ErasureFollower{store: store, client: client, logger: logger, reconnectDelay: 5 * time.Second}
+}
+
+// Run follows erasures until ctx is cancelled.
+func (f *ErasureFollower) Run(ctx context.Context) {
+	for {
+		err := f.follow(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		f.logger.Warn("customer_erasure_watch_failed", slog.String("error", err.Error()))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.reconnectDelay):
+		}
+	}
+}
+
+func (f *ErasureFollower) follow(ctx context.Context) error {
+	cursor, err := f.store.CustomerChangeCursor(ctx)
+	if err != nil {
+		return err
+	}
+	watchCtx := tenant.AppendToOutgoingContext(tenant.NewContext(ctx, tenant.All))
+	stream, err := f.client.WatchCustomers(watchCtx, &customerpb.WatchCustomersRequest{Cursor: cursor})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		// Only erasures are worth a write; the cursor is saved after each,
+		// and other changes seen again after a restart are skipped again.
+		if event.GetType() != customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_ERASED || event.GetIdn() == "" {
+			continue
+		}
+
+		erased, err := f.store.ErasePendingCustomerIDN(tenant.NewContext(ctx, event.GetTenantId()), event.GetIdn())
+		if err != nil {
+			return err
+		}
+		if erased > 0 {
+			f.logger.Info(
+				"pending_customer_idn_erased",
+				slog.String("customer_id", event.GetCustomerId()),
+				slog.Int64("shipments", erased),
+			)
+		}
+		if err := f.store.SaveCustomerChangeCursor(ctx, event.GetCursor()); err != nil {
+			return err
+		}
+	}
+}
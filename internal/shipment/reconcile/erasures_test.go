@@ -0,0 +1,97 @@
+package reconcile
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	customerpb "shipment-customer-service/api/proto"
+	"shipment-customer-service/internal/platform/tenant"
+
+	"google.golang.org/grpc"
+)
+
+type erasedIDN struct {
+	tenantID string
+	idn      string
+}
+
+type fakeErasureStore struct {
+	cursor int64
+	erased []erasedIDN
+}
+
+func (s *fakeErasureStore) ErasePendingCustomerIDN(ctx context.Context, idn string) (int64, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	s.erased = append(s.erased, erasedIDN{tenantID: tenantID, idn: idn})
+	return 1, nil
+}
+
+func (s *fakeErasureStore) CustomerChangeCursor(ctx context.Context) (int64, error) {
+	return s.cursor, nil
+}
+
+func (s *fakeErasureStore) SaveCustomerChangeCursor(ctx context.Context, seq int64) error {
+	s.cursor = seq
+	return nil
+}
+
+type fakeWatchClient struct {
+	customerpb.CustomerServiceClient
+	events []*customerpb.CustomerEvent
+	req    *customerpb.WatchCustomersRequest
+	tenant string
+}
+
+func (c *fakeWatchClient) WatchCustomers(ctx context.Context, req *customerpb.WatchCustomersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[customerpb.CustomerEvent], error) {
+	c.req = req
+	c.tenant, _ = tenant.FromContext(ctx)
+	return &fakeEventStream{events: c.events}, nil
+}
+
+type fakeEventStream struct {
+	grpc.ClientStream
+	events []*customerpb.CustomerEvent
+}
+
+func (s *fakeEventStream) Recv() (*customerpb.CustomerEvent, error) {
+	if len(s.events) == 0 {
+		return nil, io.EOF
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
+func TestErasureFollowerErasesPendingIDNs(t *testing.T) {
+	store := &fakeErasureStore{cursor: 41}
+	client := &fakeWatchClient{events: []*customerpb.CustomerEvent{
+		{Cursor: 42, TenantId: "brand-a", Type: customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_CREATED, CustomerId: "c-1", Idn: "900101300123"},
+		{Cursor: 43, TenantId: "brand-b", Type: customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_ERASED, CustomerId: "c-2", Idn: "900101300456"},
+		// Past the retention the IDN is gone and there is nothing to erase.
+		{Cursor: 44, TenantId: "brand-a", Type: customerpb.CustomerEventType_CUSTOMER_EVENT_TYPE_ERASED, CustomerId: "c-3"},
+	}}
+	follower := NewErasureFollower(store, client, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := follower.follow(context.Background()); err != io.EOF {
+		t.Fatalf("follow() error = %v, want %v", err, io.EOF)
+	}
+
+	if client.req.GetCursor() != 41 || client.req.GetFromLatest() {
+		t.Fatalf("WatchCustomers() request = %v, want cursor 41", client.req)
+	}
+	if client.tenant != tenant.All {
+		t.Fatalf("WatchCustomers() tenant = %q, want %q", client.tenant, tenant.All)
+	}
+	want := []erasedIDN{{tenantID: "brand-b", idn: "900101300456"}}
+	if len(store.erased) != len(want) || store.erased[0] != want[0] {
+		t.Fatalf("erased = %v, want %v", store.erased, want)
+	}
+	if store.cursor != 43 {
+		t.Fatalf("saved cursor = %d, want 43", store.cursor)
+	}
+}
@@ -0,0 +1,120 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/tenant"
+	"shipment-customer-service/internal/shipment/grpc"
+)
+
+type Store interface {
+	ListPendingShipments(ctx context.Context, limit int) ([]domain.Shipment, error)
+	ResolveShipmentCustomer(ctx context.Context, id, customerID string) (domain.Shipment, error)
+	UpdateShipmentStatus(ctx context.Context, id, from, to string) (domain.Shipment, error)
+}
+
+// Reconciler resolves the customers of shipments accepted in degraded mode
+// and moves them to CREATED. Shipments whose IDN was erased meanwhile cannot
+// be resolved and are cancelled.
+type Reconciler struct {
+	store          Store
+	customerClient grpc.CustomerClient
+	logger         *slog.Logger
+	interval       time.Duration
+	batchSize      int
+}
+
+func NewReconciler(store Store, customerClient grpc.CustomerClient, logger *slog.Logger) *Reconciler {
+	return &Reconciler{
+		store:          store,
+		customerClient: customerClient,
+		logger:         logger,
+		interval:       10 * time.Second,
+		batchSize:      100,
+	}
+}
+
+// Run reconciles pending shipments until ctx is cancelled.
+func (r *Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.reconcile(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Reconciler) reconcile(ctx context.Context) {
+	shipments, err := r.store.ListPendingShipments(ctx, r.batchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("pending_shipments_list_failed", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	for _, shipment := range shipments {
+		// The batch spans tenants; each customer must be resolved in the
+		// tenant of its shipment.
+		scoped := tenant.NewContext(ctx, shipment.TenantID)
+		if shipment.CustomerIDN == "" {
+			r.cancelErased(scoped, shipment)
+			continue
+		}
+		customer, err := r.customerClient.UpsertCustomer(scoped, shipment.CustomerIDN)
+		if err != nil {
+			// The customer service is most likely still down; the rest of
+			// the batch would fail the same way.
+			r.logger.Warn(
+				"shipment_customer_resolve_failed",
+				slog.String("shipment_id", shipment.ID),
+				slog.String("error", err.Error()),
+			)
+			return
+		}
+
+		_, err = r.store.ResolveShipmentCustomer(scoped, shipment.ID, customer.GetId())
+		if errors.Is(err, sql.ErrNoRows) {
+			// Cancelled while pending.
+			continue
+		}
+		if err != nil {
+			r.logger.Error("shipment_customer_update_failed", slog.String("shipment_id", shipment.ID), slog.String("error", err.Error()))
+			continue
+		}
+
+		r.logger.Info(
+			"shipment_customer_resolved",
+			slog.String("shipment_id", shipment.ID),
+			slog.String("customer_id", customer.GetId()),
+		)
+	}
+}
+
+func (r *Reconciler) cancelErased(ctx context.Context, shipment domain.Shipment) {
+	_, err := r.store.UpdateShipmentStatus(ctx, shipment.ID, domain.StatusPendingCustomer, domain.StatusCancelled)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Cancelled by the client in the meantime.
+		return
+	}
+	if err != nil {
+		r.logger.Error("shipment_cancel_failed", slog.String("shipment_id", shipment.ID), slog.String("error", err.Error()))
+		return
+	}
+	r.logger.Info(
+		"shipment_cancelled",
+		slog.String("shipment_id", shipment.ID),
+		slog.String("reason", "customer erased"),
+	)
+}
@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	apikey "shipment-customer-service/internal/domain/apikey"
+	"shipment-customer-service/internal/platform/auth"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+const apiKeyColumns = `id::text, tenant_id, name, prefix, array_to_json(scopes), created_at, last_used_at, revoked_at`
+
+func (r *PostgresRepo) CreateAPIKey(ctx context.Context, name, prefix string, hash []byte, scopes []auth.Scope) (apikey.Key, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.CreateAPIKey")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO api_keys (id, tenant_id, name, prefix, key_hash, scopes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+apiKeyColumns, uuid.NewString(), tenantID, name, prefix, hash, names)
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	if err := appendAudit(ctx, tx, tenantID, auditAPIKeyIssued, auditEntityAPIKey, key.ID, nil, apiKeyAudit(key)); err != nil {
+		return apikey.Key{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return apikey.Key{}, err
+	}
+	return key, nil
+}
+
+func (r *PostgresRepo) ListAPIKeys(ctx context.Context) ([]apikey.Key, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ListAPIKeys")
+	defer span.End()
+
+	scope, err := tenant.Scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE $1 = '*' OR tenant_id = $1
+		ORDER BY created_at
+	`, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []apikey.Key
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// GetAPIKeyByHash looks keys up across tenants: the tenant of a request is
+// only known once its key has been found.
+func (r *PostgresRepo) GetAPIKeyByHash(ctx context.Context, hash []byte) (apikey.Key, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.GetAPIKeyByHash")
+	defer span.End()
+
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE key_hash = $1
+	`, hash)
+
+	return scanAPIKey(row)
+}
+
+// RevokeAPIKey marks a key revoked, keeping the first revocation time. It
+// returns sql.ErrNoRows for unknown keys. ctx may be scoped to all tenants,
+// e.g. in the apikey command; the audit entry goes to the key's tenant.
+func (r *PostgresRepo) RevokeAPIKey(ctx context.Context, id string) (apikey.Key, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.RevokeAPIKey")
+	defer span.End()
+
+	scope, err := tenant.Scope(ctx)
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	defer tx.Rollback()
+
+	var wasRevoked bool
+	row := tx.QueryRowContext(ctx, `
+		SELECT revoked_at IS NOT NULL
+		FROM api_keys
+		WHERE id = $1 AND ($2 = '*' OR tenant_id = $2)
+		FOR UPDATE
+	`, id, scope)
+	if err := row.Scan(&wasRevoked); err != nil {
+		return apikey.Key{}, err
+	}
+
+	row = tx.QueryRowContext(ctx, `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, now())
+		WHERE id = $1
+		RETURNING `+apiKeyColumns, id)
+	key, err := scanAPIKey(row)
+	if err != nil {
+		return apikey.Key{}, err
+	}
+	// Revoking twice changes nothing and is not audited again.
+	if !wasRevoked {
+		before := apiKeyAudit(key)
+		before["revoked"] = false
+		if err := appendAudit(ctx, tx, key.TenantID, auditAPIKeyRevoked, auditEntityAPIKey, key.ID, before, apiKeyAudit(key)); err != nil {
+			return apikey.Key{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return apikey.Key{}, err
+	}
+	return key, nil
+}
+
+func (r *PostgresRepo) TouchAPIKey(ctx context.Context, id string) error {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.TouchAPIKey")
+	defer span.End()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = now() WHERE id = $1`, id)
+	return err
+}
+
+func scanAPIKey(row rowScanner) (apikey.Key, error) {
+	var key apikey.Key
+	var scopes []byte
+	var lastUsedAt, revokedAt sql.NullTime
+	if err := row.Scan(&key.ID, &key.TenantID, &key.Name, &key.Prefix, &scopes, &key.CreatedAt, &lastUsedAt, &revokedAt); err != nil {
+		return apikey.Key{}, err
+	}
+	if err := json.Unmarshal(scopes, &key.Scopes); err != nil {
+		return apikey.Key{}, err
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
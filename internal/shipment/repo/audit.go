@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	apikey "shipment-customer-service/internal/domain/apikey"
+	domain "shipment-customer-service/internal/domain/shipment"
+	webhook "shipment-customer-service/internal/domain/webhook"
+	"shipment-customer-service/internal/platform/audit"
+	"shipment-customer-service/internal/platform/telemetry"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+const (
+	auditEntityShipment     = "shipment"
+	auditEntityWebhook      = "webhook_subscription"
+	auditEntityDelivery     = "webhook_delivery"
+	auditEntityAPIKey       = "api_key"
+	auditShipmentCreated    = "shipment.created"
+	auditShipmentResolved   = "shipment.customer_resolved"
+	auditShipmentStatus     = "shipment.status_changed"
+	auditShipmentCancelled  = "shipment.cancelled"
+	auditWebhookCreated     = "webhook.created"
+	auditWebhookDeleted     = "webhook.deleted"
+	auditWebhookRedelivered = "webhook_delivery.redelivered"
+	auditAPIKeyIssued       = "api_key.issued"
+	auditAPIKeyRevoked      = "api_key.revoked"
+)
+
+const auditColumns = `seq, tenant_id, actor, action, entity_type, entity_id, changes, request_id, trace_id, occurred_at, prev_hash, hash`
+
+// ListAuditEntries returns the audit log of the tenant in ctx, oldest first.
+// It includes the entries written by the customer service, which shares the
+// table.
+func (r *PostgresRepo) ListAuditEntries(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ListAuditEntries")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+auditColumns+`
+		FROM audit_log
+		WHERE tenant_id = $1
+			AND seq > $2
+			AND ($3 = '' OR entity_type = $3)
+			AND ($4 = '' OR entity_id = $4)
+			AND ($5 = '' OR actor = $5)
+			AND ($6 = '' OR request_id = $6)
+			AND ($7::timestamptz IS NULL OR occurred_at >= $7)
+			AND ($8::timestamptz IS NULL OR occurred_at < $8)
+		ORDER BY seq
+		LIMIT $9
+	`, tenantID, filter.AfterSeq, filter.EntityType, filter.EntityID, filter.Actor, filter.RequestID,
+		nullTime(filter.From), nullTime(filter.To), filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []audit.Entry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// VerifyAuditChain recomputes up to filter.Limit entries of the hash chain of
+// the tenant in ctx, following filter.After. The entry at the checkpoint must
+// still carry the hash it was verified with, so the chain cannot be changed
+// behind it unnoticed.
+func (r *PostgresRepo) VerifyAuditChain(ctx context.Context, filter audit.VerifyFilter) (audit.VerifyResult, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.VerifyAuditChain")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return audit.VerifyResult{}, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return audit.VerifyResult{}, err
+	}
+	defer tx.Rollback()
+
+	if filter.After.Seq > 0 {
+		var hash string
+		err := tx.QueryRowContext(ctx, `
+			SELECT hash FROM audit_log WHERE tenant_id = $1 AND seq = $2
+		`, tenantID, filter.After.Seq).Scan(&hash)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && hash != filter.After.Hash) {
+			return audit.VerifyResult{BrokenAt: filter.After.Seq}, nil
+		}
+		if err != nil {
+			return audit.VerifyResult{}, err
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+auditColumns+`
+		FROM audit_log
+		WHERE tenant_id = $1 AND seq > $2
+		ORDER BY seq
+		LIMIT $3
+	`, tenantID, filter.After.Seq, filter.Limit)
+	if err != nil {
+		return audit.VerifyResult{}, err
+	}
+	defer rows.Close()
+
+	verifier := audit.ResumeVerifier(filter.After)
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return audit.VerifyResult{}, err
+		}
+		verifier.Add(entry)
+	}
+	return verifier.Result(), rows.Err()
+}
+
+func scanAuditEntry(row rowScanner) (audit.Entry, error) {
+	var entry audit.Entry
+	var changes []byte
+	if err := row.Scan(
+		&entry.Seq, &entry.TenantID, &entry.Actor, &entry.Action, &entry.EntityType, &entry.EntityID, &changes,
+		&entry.RequestID, &entry.TraceID, &entry.OccurredAt, &entry.PrevHash, &entry.Hash,
+	); err != nil {
+		return audit.Entry{}, err
+	}
+	if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+		return audit.Entry{}, err
+	}
+	return entry, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// appendAudit records a change made in tx. before is nil for created and
+// after for deleted entities.
+func appendAudit(ctx context.Context, tx *sql.Tx, tenantID, action, entityType, entityID string, before, after audit.Fields) error {
+	_, err := audit.Append(ctx, tx, audit.Entry{
+		TenantID:   tenantID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    audit.Diff(before, after),
+	})
+	return err
+}
+
+// shipmentAudit is the audited state of a shipment. A pending shipment's IDN
+// is masked like in logs.
+func shipmentAudit(shipment domain.Shipment) audit.Fields {
+	fields := audit.Fields{
+		"route":       shipment.Route,
+		"price":       shipment.Price,
+		"status":      shipment.Status,
+		"customer_id": shipment.CustomerID,
+	}
+	if shipment.CustomerIDN != "" {
+		fields["customer_idn"] = telemetry.MaskIDN(shipment.CustomerIDN)
+	}
+	return fields
+}
+
+// subscriptionAudit leaves out the signing secret.
+func subscriptionAudit(subscription webhook.Subscription) audit.Fields {
+	return audit.Fields{"url": subscription.URL, "event_types": subscription.EventTypes}
+}
+
+func deliveryAudit(delivery webhook.Delivery) audit.Fields {
+	return audit.Fields{"status": delivery.Status, "attempts": delivery.Attempts}
+}
+
+func apiKeyAudit(key apikey.Key) audit.Fields {
+	return audit.Fields{"name": key.Name, "prefix": key.Prefix, "scopes": key.Scopes, "revoked": key.RevokedAt != nil}
+}
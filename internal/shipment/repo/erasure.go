@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+// ErasePendingCustomerIDN drops idn from the pending shipments of the tenant
+// in ctx after the customer was erased, and returns how many shipments held
+// it. The reconciler then cancels them, as they can no longer be resolved.
+func (r *PostgresRepo) ErasePendingCustomerIDN(ctx context.Context, idn string) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ErasePendingCustomerIDN")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE shipments
+		SET customer_idn = NULL, customer_idn_index = NULL, customer_idn_ciphertext = NULL, customer_idn_key_id = NULL
+		WHERE tenant_id = $1 AND customer_idn_index = $2 AND status = $3
+	`, tenantID, r.keyring.BlindIndex(idn), domain.StatusPendingCustomer)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CustomerChangeCursor returns how far the customer change stream has been
+// followed, or 0 if it never was.
+func (r *PostgresRepo) CustomerChangeCursor(ctx context.Context) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.CustomerChangeCursor")
+	defer span.End()
+
+	tx, err := tenant.BeginTx(tenant.NewContext(ctx, tenant.All), r.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var seq int64
+	err = tx.QueryRowContext(ctx, `SELECT seq FROM customer_change_cursor`).Scan(&seq)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return seq, err
+}
+
+func (r *PostgresRepo) SaveCustomerChangeCursor(ctx context.Context, seq int64) error {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.SaveCustomerChangeCursor")
+	defer span.End()
+
+	return r.execAllTenants(ctx, `
+		INSERT INTO customer_change_cursor (seq) VALUES ($1)
+		ON CONFLICT (id) DO UPDATE SET seq = excluded.seq
+	`, seq)
+}
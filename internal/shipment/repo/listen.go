@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+const shipmentEventsChannel = "shipment_events"
+
+// ListenShipmentEvents holds a dedicated connection listening for committed
+// shipment events and sends them to events. It blocks until ctx is done or
+// the connection fails; callers are expected to reconnect and replay missed
+// events with ListEventsSince.
+func (r *PostgresRepo) ListenShipmentEvents(ctx context.Context, events chan<- domain.Event) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection %T", driverConn)
+		}
+		pgConn := stdConn.Conn()
+
+		if _, err := pgConn.Exec(ctx, "LISTEN "+pgx.Identifier{shipmentEventsChannel}.Sanitize()); err != nil {
+			return err
+		}
+
+		for {
+			notification, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+
+			var event domain.Event
+			if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	})
+}
+
+// ListEventsSince returns up to limit outbox events of the tenant in ctx after
+// seq, optionally restricted to one shipment or one customer.
+func (r *PostgresRepo) ListEventsSince(ctx context.Context, seq int64, shipmentID, customerID string, limit int) ([]domain.Event, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ListEventsSince")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, event_id::text, tenant_id, event_type, aggregate_id::text, occurred_at, payload
+		FROM outbox
+		WHERE tenant_id = $1
+			AND id > $2
+			AND ($3 = '' OR aggregate_id::text = $3)
+			AND ($4 = '' OR payload->>'customer_id' = $4)
+		ORDER BY id
+		LIMIT $5
+	`, tenantID, seq, shipmentID, customerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+	"strings"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// otherRoute is the route attribute of shipments on routes outside the
+// allowlist.
+const otherRoute = "other"
+
+// metrics are business counters, recorded once the transaction they count
+// has committed.
+type metrics struct {
+	created metric.Int64Counter
+	// routes is the allowlist of normalised routes counted on their own.
+	routes map[string]bool
+}
+
+// newMetrics cannot fail the repository: instruments that could not be
+// created are reported to the OpenTelemetry error handler and record nothing.
+func newMetrics() metrics {
+	created, err := otel.Meter("shipment-db").Int64Counter(
+		"shipments.created",
+		metric.WithDescription("Shipments created, by status and route"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return metrics{created: created, routes: map[string]bool{}}
+}
+
+// shipmentsCreated counts by status and route. The route is free text from
+// the client, so only allowlisted routes get series of their own and the
+// rest are counted as "other".
+func (m metrics) shipmentsCreated(ctx context.Context, shipments []domain.Shipment) {
+	for _, shipment := range shipments {
+		m.created.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("shipment.status", shipment.Status),
+			attribute.String("shipment.route", m.route(shipment.Route)),
+		))
+	}
+}
+
+func (m metrics) route(route string) string {
+	if route = normalizeRoute(route); m.routes[route] {
+		return route
+	}
+	return otherRoute
+}
+
+// normalizeRoute makes spellings of the same route compare equal:
+// " almaty -> Astana" becomes "ALMATY->ASTANA".
+func normalizeRoute(route string) string {
+	stops := strings.Split(route, "->")
+	for i, stop := range stops {
+		stops[i] = strings.ToUpper(strings.Join(strings.Fields(stop), " "))
+	}
+	return strings.Join(stops, "->")
+}
@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestShipmentsCreatedByAllowlistedRoute(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	r := NewPostgresRepo(nil, nil, nil, WithMetricRoutes("ALMATY->ASTANA", " astana -> almaty "))
+	r.metrics.shipmentsCreated(context.Background(), []domain.Shipment{
+		{Route: "ALMATY->ASTANA", Status: domain.StatusCreated},
+		{Route: "almaty ->  astana", Status: domain.StatusCreated},
+		{Route: "ASTANA->ALMATY", Status: domain.StatusPendingCustomer},
+		{Route: "ALMATY->SHYMKENT", Status: domain.StatusCreated},
+		{Route: "anything a client types", Status: domain.StatusCreated},
+	})
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	got := map[string]int64{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if m.Name != "shipments.created" || !ok {
+				continue
+			}
+			for _, point := range sum.DataPoints {
+				route, _ := point.Attributes.Value(attribute.Key("shipment.route"))
+				status, _ := point.Attributes.Value(attribute.Key("shipment.status"))
+				got[status.AsString()+" "+route.AsString()] += point.Value
+			}
+		}
+	}
+
+	want := map[string]int64{
+		domain.StatusCreated + " ALMATY->ASTANA":         2,
+		domain.StatusPendingCustomer + " ASTANA->ALMATY": 1,
+		domain.StatusCreated + " other":                  2,
+	}
+	if !maps.Equal(got, want) {
+		t.Fatalf("shipments created = %v, want %v", got, want)
+	}
+}
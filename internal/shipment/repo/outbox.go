@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+// insertOutboxEvent records an event inside tx and announces it on the
+// shipment events channel; Postgres only delivers the notification if tx
+// commits. It must come after every other statement of tx: the lock it takes
+// is held until commit, so outbox ids are handed out in commit order and a
+// stream that has delivered an event has seen every event before it.
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, eventType string, data domain.EventData) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('outbox'))`); err != nil {
+		return err
+	}
+
+	event := domain.Event{ID: uuid.NewString(), Type: eventType, ShipmentID: data.ShipmentID, Data: data}
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO outbox (event_id, tenant_id, event_type, aggregate_id, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, occurred_at
+	`, event.ID, data.TenantID, event.Type, event.ShipmentID, payload)
+	if err := row.Scan(&event.Seq, &event.OccurredAt); err != nil {
+		return err
+	}
+
+	notification, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, shipmentEventsChannel, string(notification))
+	return err
+}
+
+// ClaimOutboxEvents leases up to limit unpublished events of all tenants,
+// oldest first, so that concurrent relays do not pick the same rows. A lease
+// that is not followed by MarkOutboxPublished expires and the event is
+// delivered again.
+func (r *PostgresRepo) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]domain.Event, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ClaimOutboxEvents")
+	defer span.End()
+
+	tx, err := tenant.BeginTx(tenant.NewContext(ctx, tenant.All), r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE outbox
+		SET locked_until = now() + $2 * interval '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE published_at IS NULL AND (locked_until IS NULL OR locked_until < now())
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_id::text, tenant_id, event_type, aggregate_id::text, occurred_at, payload
+	`, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	events, err := scanEvents(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not preserve the subquery order.
+	slices.SortFunc(events, func(a, b domain.Event) int { return cmp.Compare(a.Seq, b.Seq) })
+	return events, nil
+}
+
+func (r *PostgresRepo) MarkOutboxPublished(ctx context.Context, seq int64) error {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.MarkOutboxPublished")
+	defer span.End()
+
+	return r.execAllTenants(ctx, `
+		UPDATE outbox
+		SET published_at = now(), locked_until = NULL, attempts = attempts + 1, last_error = NULL
+		WHERE id = $1
+	`, seq)
+}
+
+// MarkOutboxFailed records a failed publish attempt and keeps the event
+// leased until retryAfter has passed.
+func (r *PostgresRepo) MarkOutboxFailed(ctx context.Context, seq int64, cause string, retryAfter time.Duration) error {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.MarkOutboxFailed")
+	defer span.End()
+
+	return r.execAllTenants(ctx, `
+		UPDATE outbox
+		SET locked_until = now() + $3 * interval '1 millisecond', attempts = attempts + 1, last_error = $2
+		WHERE id = $1
+	`, seq, cause, retryAfter.Milliseconds())
+}
+
+// execAllTenants runs a statement of a background worker, which is not bound
+// to a tenant.
+func (r *PostgresRepo) execAllTenants(ctx context.Context, query string, args ...any) error {
+	tx, err := tenant.BeginTx(tenant.NewContext(ctx, tenant.All), r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func scanEvents(rows *sql.Rows) ([]domain.Event, error) {
+	var events []domain.Event
+	for rows.Next() {
+		var event domain.Event
+		var tenantID string
+		var payload []byte
+		if err := rows.Scan(&event.Seq, &event.ID, &tenantID, &event.Type, &event.ShipmentID, &event.OccurredAt, &payload); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payload, &event.Data); err != nil {
+			return nil, err
+		}
+		// Payloads written before tenants existed do not carry one.
+		event.Data.TenantID = tenantID
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
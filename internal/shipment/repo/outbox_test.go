@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace/noop"
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+// testDB connects to the migrated database named by TEST_POSTGRES_DSN and
+// skips the test when there is none.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	return db
+}
+
+// TestOutboxSeqFollowsCommitOrder starts two writes so that the one holding
+// the lower outbox id would commit last, and checks that a stream following
+// notifications by seq still delivers both.
+func TestOutboxSeqFollowsCommitOrder(t *testing.T) {
+	db := testDB(t)
+	r := NewPostgresRepo(db, noop.NewTracerProvider().Tracer(""), nil)
+	// A tenant of its own keeps earlier runs out of the feed.
+	tenantID := "outbox-" + uuid.NewString()[:8]
+	ctx, cancel := context.WithTimeout(tenant.NewContext(context.Background(), tenantID), 10*time.Second)
+	defer cancel()
+
+	notifications := make(chan domain.Event, 16)
+	listenCtx, stopListening := context.WithCancel(ctx)
+	defer stopListening()
+	go r.ListenShipmentEvents(listenCtx, notifications)
+	// LISTEN has to be in place before the first commit.
+	time.Sleep(200 * time.Millisecond)
+
+	insert := func(tx *sql.Tx) error {
+		data := domain.EventData{TenantID: tenantID, ShipmentID: uuid.NewString(), Status: domain.StatusCreated}
+		return insertOutboxEvent(ctx, tx, domain.EventShipmentCreated, data)
+	}
+
+	first, err := tenant.BeginTx(ctx, db)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	defer first.Rollback()
+	if err := insert(first); err != nil {
+		t.Fatalf("insertOutboxEvent() error = %v", err)
+	}
+
+	secondDone := make(chan error, 1)
+	go func() {
+		second, err := tenant.BeginTx(ctx, db)
+		if err != nil {
+			secondDone <- err
+			return
+		}
+		defer second.Rollback()
+		if err := insert(second); err != nil {
+			secondDone <- err
+			return
+		}
+		secondDone <- second.Commit()
+	}()
+
+	// Give the second write every chance to commit ahead of the first.
+	time.Sleep(200 * time.Millisecond)
+	if err := first.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := <-secondDone; err != nil {
+		t.Fatalf("second write error = %v", err)
+	}
+
+	var lastSeq int64
+	var delivered int
+	for delivered < 2 {
+		select {
+		case event := <-notifications:
+			if event.Data.TenantID != tenantID {
+				continue
+			}
+			if event.Seq <= lastSeq {
+				t.Fatalf("event %d notified after %d, a stream would skip it", event.Seq, lastSeq)
+			}
+			lastSeq = event.Seq
+			delivered++
+		case <-ctx.Done():
+			t.Fatalf("got %d notifications, want 2", delivered)
+		}
+	}
+
+	events, err := r.ListEventsSince(ctx, 0, "", "", 10)
+	if err != nil {
+		t.Fatalf("ListEventsSince() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("ListEventsSince() returned %d events, want 2", len(events))
+	}
+}
@@ -8,15 +8,43 @@ import (
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/trace"
 	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/keyring"
+	"shipment-customer-service/internal/platform/tenant"
 )
 
+// shipmentColumns select a shipment for scanShipment. customer_idn is the
+// plaintext IDN of pending shipments stored before it was encrypted.
+const shipmentColumns = `id::text, tenant_id, route, price::text, status, COALESCE(customer_id::text, ''), customer_idn, customer_idn_ciphertext, customer_idn_key_id, created_at`
+
 type PostgresRepo struct {
 	db     *sql.DB
 	tracer trace.Tracer
+	// keyring encrypts the IDNs of pending shipments. It is the customer
+	// service's, so that erasing a customer finds them by blind index.
+	keyring *keyring.Keyring
+	metrics metrics
 }
 
-func NewPostgresRepo(db *sql.DB, tracer trace.Tracer) *PostgresRepo {
-	return &PostgresRepo{db: db, tracer: tracer}
+type Option func(*PostgresRepo)
+
+// WithMetricRoutes lists the routes that shipments.created counts on their
+// own; shipments on any other route are counted as "other".
+func WithMetricRoutes(routes ...string) Option {
+	return func(r *PostgresRepo) {
+		for _, route := range routes {
+			r.metrics.routes[normalizeRoute(route)] = true
+		}
+	}
+}
+
+// NewPostgresRepo takes the keyring for pending shipments; keys may be nil if
+// the repository is only used for API keys.
+func NewPostgresRepo(db *sql.DB, tracer trace.Tracer, keys *keyring.Keyring, opts ...Option) *PostgresRepo {
+	r := &PostgresRepo{db: db, tracer: tracer, keyring: keys, metrics: newMetrics()}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 func (r *PostgresRepo) Ping(ctx context.Context) error {
@@ -27,23 +55,231 @@ func (r *PostgresRepo) CreateShipment(ctx context.Context, route string, price f
 	ctx, span := r.tracer.Start(ctx, "shipment.repo.CreateShipment")
 	defer span.End()
 
-	row := r.db.QueryRowContext(ctx, `
-		INSERT INTO shipments (id, route, price, customer_id)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id::text, route, price::text, status, customer_id::text, created_at
-	`, uuid.NewString(), route, price, customerID)
+	shipments, err := r.createShipments(ctx, []domain.Shipment{
+		{Route: route, Price: price, Status: domain.StatusCreated, CustomerID: customerID},
+	})
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	return shipments[0], nil
+}
+
+// CreatePendingShipment stores a shipment whose customer could not be
+// resolved yet, keeping the IDN encrypted until ResolveShipmentCustomer
+// replaces it or the shipment is cancelled.
+func (r *PostgresRepo) CreatePendingShipment(ctx context.Context, route string, price float64, customerIDN string) (domain.Shipment, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.CreatePendingShipment")
+	defer span.End()
 
-	var shipment domain.Shipment
-	var priceText string
-	if err := row.Scan(&shipment.ID, &shipment.Route, &priceText, &shipment.Status, &shipment.CustomerID, &shipment.CreatedAt); err != nil {
+	shipments, err := r.createShipments(ctx, []domain.Shipment{
+		{Route: route, Price: price, Status: domain.StatusPendingCustomer, CustomerIDN: customerIDN},
+	})
+	if err != nil {
 		return domain.Shipment{}, err
 	}
+	return shipments[0], nil
+}
 
-	parsedPrice, err := strconv.ParseFloat(priceText, 64)
+// CreateShipments stores all shipments in one transaction. Each must carry
+// either a customer ID or, while PENDING_CUSTOMER, the customer IDN.
+func (r *PostgresRepo) CreateShipments(ctx context.Context, shipments []domain.Shipment) ([]domain.Shipment, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.CreateShipments")
+	defer span.End()
+
+	return r.createShipments(ctx, shipments)
+}
+
+func (r *PostgresRepo) createShipments(ctx context.Context, shipments []domain.Shipment) ([]domain.Shipment, error) {
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := consumeCreationQuota(ctx, tx, tenantID, len(shipments)); err != nil {
+		return nil, err
+	}
+
+	created := make([]domain.Shipment, 0, len(shipments))
+	for _, draft := range shipments {
+		var index, ciphertext []byte
+		var keyID sql.NullString
+		if draft.CustomerIDN != "" {
+			id, sealed, err := r.keyring.Encrypt([]byte(draft.CustomerIDN))
+			if err != nil {
+				return nil, err
+			}
+			index, ciphertext, keyID = r.keyring.BlindIndex(draft.CustomerIDN), sealed, sql.NullString{String: id, Valid: true}
+		}
+		row := tx.QueryRowContext(ctx, `
+			INSERT INTO shipments (id, tenant_id, route, price, status, customer_id, customer_idn_index, customer_idn_ciphertext, customer_idn_key_id)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid, $7, $8, $9)
+			RETURNING `+shipmentColumns, uuid.NewString(), tenantID, draft.Route, draft.Price, draft.Status, draft.CustomerID, index, ciphertext, keyID)
+
+		shipment, err := r.scanShipment(row)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := appendAudit(ctx, tx, tenantID, auditShipmentCreated, auditEntityShipment, shipment.ID, nil, shipmentAudit(shipment)); err != nil {
+			return nil, err
+		}
+		created = append(created, shipment)
+	}
+	for _, shipment := range created {
+		if err := insertOutboxEvent(ctx, tx, domain.EventShipmentCreated, domain.NewEventData(shipment, "")); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	r.metrics.shipmentsCreated(ctx, created)
+
+	return created, nil
+}
+
+// ListPendingShipments returns up to limit shipments still waiting for their
+// customer, oldest first, across all tenants.
+func (r *PostgresRepo) ListPendingShipments(ctx context.Context, limit int) ([]domain.Shipment, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ListPendingShipments")
+	defer span.End()
+
+	tx, err := tenant.BeginTx(tenant.NewContext(ctx, tenant.All), r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+shipmentColumns+`
+		FROM shipments
+		WHERE status = $1
+		ORDER BY created_at
+		LIMIT $2
+	`, domain.StatusPendingCustomer, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shipments []domain.Shipment
+	for rows.Next() {
+		shipment, err := r.scanShipment(rows)
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, shipment)
+	}
+	return shipments, rows.Err()
+}
+
+// EncryptPendingIDNs encrypts up to limit IDNs of pending shipments stored in
+// plaintext before encryption was introduced, across all tenants. It returns
+// the number of shipments rewritten.
+func (r *PostgresRepo) EncryptPendingIDNs(ctx context.Context, limit int) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.EncryptPendingIDNs")
+	defer span.End()
+
+	tx, err := tenant.BeginTx(tenant.NewContext(ctx, tenant.All), r.db)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id::text, customer_idn FROM shipments
+		WHERE customer_idn IS NOT NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return 0, err
+	}
+	pending := make(map[string]string)
+	for rows.Next() {
+		var id, idn string
+		if err := rows.Scan(&id, &idn); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending[id] = idn
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for id, idn := range pending {
+		keyID, ciphertext, err := r.keyring.Encrypt([]byte(idn))
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE shipments
+			SET customer_idn = NULL, customer_idn_index = $2, customer_idn_ciphertext = $3, customer_idn_key_id = $4
+			WHERE id = $1
+		`, id, r.keyring.BlindIndex(idn), ciphertext, keyID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(pending), nil
+}
+
+// ResolveShipmentCustomer attaches the customer to a pending shipment of the
+// tenant in ctx, drops the stored IDN and moves the shipment to CREATED. It
+// returns sql.ErrNoRows when the shipment is no longer pending, e.g. because
+// it was cancelled.
+func (r *PostgresRepo) ResolveShipmentCustomer(ctx context.Context, id, customerID string) (domain.Shipment, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ResolveShipmentCustomer")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
 	if err != nil {
 		return domain.Shipment{}, err
 	}
-	shipment.Price = parsedPrice
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		UPDATE shipments
+		SET customer_id = $3, status = $5,
+			customer_idn = NULL, customer_idn_index = NULL, customer_idn_ciphertext = NULL, customer_idn_key_id = NULL
+		WHERE tenant_id = $1 AND id = $2 AND status = $4
+		RETURNING `+shipmentColumns, tenantID, id, customerID, domain.StatusPendingCustomer, domain.StatusCreated)
+
+	shipment, err := r.scanShipment(row)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+
+	before := shipment
+	before.Status, before.CustomerID = domain.StatusPendingCustomer, ""
+	if err := appendAudit(ctx, tx, tenantID, auditShipmentResolved, auditEntityShipment, shipment.ID, shipmentAudit(before), shipmentAudit(shipment)); err != nil {
+		return domain.Shipment{}, err
+	}
+	if err := insertOutboxEvent(ctx, tx, domain.EventStatusChanged, domain.NewEventData(shipment, domain.StatusPendingCustomer)); err != nil {
+		return domain.Shipment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Shipment{}, err
+	}
 
 	return shipment, nil
 }
@@ -52,15 +288,129 @@ func (r *PostgresRepo) GetShipment(ctx context.Context, id string) (domain.Shipm
 	ctx, span := r.tracer.Start(ctx, "shipment.repo.GetShipment")
 	defer span.End()
 
-	row := r.db.QueryRowContext(ctx, `
-		SELECT id::text, route, price::text, status, customer_id::text, created_at
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		SELECT `+shipmentColumns+`
+		FROM shipments
+		WHERE tenant_id = $1 AND id = $2
+	`, tenantID, id)
+
+	return r.scanShipment(row)
+}
+
+func (r *PostgresRepo) ListShipments(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ListShipments")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Keyset pagination on (created_at, id) keeps pages stable while new
+	// shipments are being inserted.
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+shipmentColumns+`
 		FROM shipments
-		WHERE id = $1
-	`, id)
+		WHERE tenant_id = $1
+			AND ($2 = '' OR customer_id = NULLIF($2, '')::uuid)
+			AND ($3 = '' OR status = $3)
+			AND ($4 = '' OR (created_at, id) < (SELECT created_at, id FROM shipments WHERE tenant_id = $1 AND id = NULLIF($4, '')::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5
+	`, tenantID, filter.CustomerID, filter.Status, filter.After, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shipments []domain.Shipment
+	for rows.Next() {
+		shipment, err := r.scanShipment(rows)
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, shipment)
+	}
+	return shipments, rows.Err()
+}
+
+// UpdateShipmentStatus moves a shipment from one status to another and records
+// the matching outbox event and audit entry in the same transaction. The
+// stored customer IDN is cleared on every update. It returns sql.ErrNoRows
+// when the shipment does not exist or is no longer in status from.
+func (r *PostgresRepo) UpdateShipmentStatus(ctx context.Context, id, from, to string) (domain.Shipment, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.UpdateShipmentStatus")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	defer tx.Rollback()
+
+	// Only pending shipments carry an IDN. They leave that status here only
+	// by being cancelled, and no other status needs one.
+	row := tx.QueryRowContext(ctx, `
+		UPDATE shipments
+		SET status = $4,
+			customer_idn = NULL, customer_idn_index = NULL, customer_idn_ciphertext = NULL, customer_idn_key_id = NULL
+		WHERE tenant_id = $1 AND id = $2 AND status = $3
+		RETURNING `+shipmentColumns, tenantID, id, from, to)
+
+	shipment, err := r.scanShipment(row)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
 
+	eventType, action := domain.EventStatusChanged, auditShipmentStatus
+	if to == domain.StatusCancelled {
+		eventType, action = domain.EventCancelled, auditShipmentCancelled
+	}
+	before := shipment
+	before.Status = from
+	if err := appendAudit(ctx, tx, tenantID, action, auditEntityShipment, shipment.ID, shipmentAudit(before), shipmentAudit(shipment)); err != nil {
+		return domain.Shipment{}, err
+	}
+	if err := insertOutboxEvent(ctx, tx, eventType, domain.NewEventData(shipment, from)); err != nil {
+		return domain.Shipment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Shipment{}, err
+	}
+
+	return shipment, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *PostgresRepo) scanShipment(row rowScanner) (domain.Shipment, error) {
 	var shipment domain.Shipment
 	var priceText string
-	if err := row.Scan(&shipment.ID, &shipment.Route, &priceText, &shipment.Status, &shipment.CustomerID, &shipment.CreatedAt); err != nil {
+	var plaintext, keyID sql.NullString
+	var ciphertext []byte
+	if err := row.Scan(&shipment.ID, &shipment.TenantID, &shipment.Route, &priceText, &shipment.Status, &shipment.CustomerID, &plaintext, &ciphertext, &keyID, &shipment.CreatedAt); err != nil {
 		return domain.Shipment{}, err
 	}
 
@@ -70,5 +420,16 @@ func (r *PostgresRepo) GetShipment(ctx context.Context, id string) (domain.Shipm
 	}
 	shipment.Price = parsedPrice
 
+	switch {
+	case ciphertext != nil && keyID.Valid:
+		decrypted, err := r.keyring.Decrypt(keyID.String, ciphertext)
+		if err != nil {
+			return domain.Shipment{}, err
+		}
+		shipment.CustomerIDN = string(decrypted)
+	case plaintext.Valid:
+		shipment.CustomerIDN = plaintext.String
+	}
+
 	return shipment, nil
 }
@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/auth"
+)
+
+// consumeCreationQuota counts n new shipments against the daily quota of the
+// caller in ctx. The count is part of tx, so shipments that are not created
+// after all give their quota back. Calls without a principal, from background
+// work or unauthenticated internal callers, are not limited.
+func consumeCreationQuota(ctx context.Context, tx *sql.Tx, tenantID string, n int) error {
+	clientID := auth.PrincipalID(ctx)
+	if clientID == "" {
+		return nil
+	}
+
+	var limit int
+	err := tx.QueryRowContext(ctx, `
+		SELECT daily_limit
+		FROM creation_quotas
+		WHERE tenant_id IN ($1, '*') AND client_id IN ($2, '*')
+		ORDER BY client_id = '*', tenant_id = '*'
+		LIMIT 1
+	`, tenantID, clientID).Scan(&limit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The upsert locks the caller's row, so concurrent creations are counted
+	// one after the other.
+	var used int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO creation_quota_usage (tenant_id, client_id, day, used)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, client_id, day) DO UPDATE
+		SET used = creation_quota_usage.used + EXCLUDED.used
+		RETURNING used
+	`, tenantID, clientID, domain.QuotaDay(time.Now()), n).Scan(&used)
+	if err != nil {
+		return err
+	}
+	if used > limit {
+		return domain.ErrQuotaExceeded
+	}
+	return nil
+}
@@ -0,0 +1,410 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	domain "shipment-customer-service/internal/domain/shipment"
+	webhook "shipment-customer-service/internal/domain/webhook"
+	"shipment-customer-service/internal/platform/tenant"
+)
+
+const subscriptionColumns = `id::text, url, secret, array_to_json(event_types), created_at`
+
+func (r *PostgresRepo) CreateWebhookSubscription(ctx context.Context, url, secret string, eventTypes []string) (webhook.Subscription, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.CreateWebhookSubscription")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO webhook_subscriptions (id, tenant_id, url, secret, event_types)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+subscriptionColumns, uuid.NewString(), tenantID, url, secret, eventTypes)
+
+	subscription, err := scanSubscription(row)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	if err := appendAudit(ctx, tx, tenantID, auditWebhookCreated, auditEntityWebhook, subscription.ID, nil, subscriptionAudit(subscription)); err != nil {
+		return webhook.Subscription{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return webhook.Subscription{}, err
+	}
+	return subscription, nil
+}
+
+func (r *PostgresRepo) GetWebhookSubscription(ctx context.Context, id string) (webhook.Subscription, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.GetWebhookSubscription")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		SELECT `+subscriptionColumns+`
+		FROM webhook_subscriptions
+		WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL
+	`, tenantID, id)
+
+	return scanSubscription(row)
+}
+
+func (r *PostgresRepo) ListWebhookSubscriptions(ctx context.Context) ([]webhook.Subscription, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ListWebhookSubscriptions")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+subscriptionColumns+`
+		FROM webhook_subscriptions
+		WHERE tenant_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []webhook.Subscription
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+// DeleteWebhookSubscription soft-deletes a subscription so that its delivery
+// log stays available. It returns sql.ErrNoRows for unknown subscriptions.
+func (r *PostgresRepo) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.DeleteWebhookSubscription")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		UPDATE webhook_subscriptions SET deleted_at = now()
+		WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL
+		RETURNING `+subscriptionColumns, tenantID, id)
+	subscription, err := scanSubscription(row)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(ctx, tx, tenantID, auditWebhookDeleted, auditEntityWebhook, subscription.ID, subscriptionAudit(subscription), nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// EnqueueWebhookDeliveries creates a pending delivery of event for every
+// active subscription of the event's tenant interested in its type. Enqueuing
+// the same event twice is a no-op, which keeps at-least-once outbox relaying
+// safe.
+func (r *PostgresRepo) EnqueueWebhookDeliveries(ctx context.Context, event domain.Event) error {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.EnqueueWebhookDeliveries")
+	defer span.End()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return r.execAllTenants(ctx, `
+		INSERT INTO webhook_deliveries (id, tenant_id, subscription_id, event_id, event_type, payload)
+		SELECT gen_random_uuid(), tenant_id, id, $1, $2, $3
+		FROM webhook_subscriptions
+		WHERE tenant_id = $4 AND deleted_at IS NULL AND ($2 = ANY(event_types) OR '*' = ANY(event_types))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`, event.ID, event.Type, payload, event.Data.TenantID)
+}
+
+// ClaimWebhookDeliveries leases up to limit due deliveries of all tenants by
+// pushing their next attempt into the future, so concurrent dispatchers skip
+// them.
+func (r *PostgresRepo) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ClaimWebhookDeliveries")
+	defer span.End()
+
+	tx, err := tenant.BeginTx(tenant.NewContext(ctx, tenant.All), r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = now() + $2 * interval '1 millisecond'
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT dd.id FROM webhook_deliveries dd
+			JOIN webhook_subscriptions ds ON ds.id = dd.subscription_id
+			WHERE dd.status = 'PENDING' AND dd.next_attempt_at <= now() AND ds.deleted_at IS NULL
+			ORDER BY dd.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF dd SKIP LOCKED
+		)
+		RETURNING d.id::text, d.subscription_id::text, s.url, s.secret, d.event_id::text, d.event_type, d.payload, d.attempts
+	`, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []webhook.Delivery
+	for rows.Next() {
+		var delivery webhook.Delivery
+		if err := rows.Scan(
+			&delivery.ID, &delivery.SubscriptionID, &delivery.URL, &delivery.Secret,
+			&delivery.EventID, &delivery.EventType, &delivery.Payload, &delivery.Attempts,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt appends attempt to the delivery log and moves the
+// delivery to status, scheduling the next attempt at nextAttemptAt.
+func (r *PostgresRepo) RecordWebhookAttempt(ctx context.Context, deliveryID string, attempt webhook.Attempt, status string, nextAttemptAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.RecordWebhookAttempt")
+	defer span.End()
+
+	tx, err := tenant.BeginTx(tenant.NewContext(ctx, tenant.All), r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO webhook_delivery_attempts (delivery_id, number, status_code, error, duration_ms, attempted_at)
+		VALUES ($1, $2, NULLIF($3, 0), NULLIF($4, ''), $5, $6)
+	`, deliveryID, attempt.Number, attempt.StatusCode, attempt.Error, attempt.Duration.Milliseconds(), attempt.AttemptedAt); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2,
+			attempts = $3,
+			next_attempt_at = $4,
+			last_status_code = NULLIF($5, 0),
+			last_error = NULLIF($6, ''),
+			delivered_at = CASE WHEN $2 = 'DELIVERED' THEN $7 ELSE delivered_at END
+		WHERE id = $1
+	`, deliveryID, status, attempt.Number, nextAttemptAt, attempt.StatusCode, attempt.Error, attempt.AttemptedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresRepo) ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) ([]webhook.Delivery, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.ListWebhookDeliveries")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE tenant_id = $1 AND subscription_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, tenantID, subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []webhook.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *PostgresRepo) GetWebhookDelivery(ctx context.Context, subscriptionID, deliveryID string) (webhook.Delivery, []webhook.Attempt, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.GetWebhookDelivery")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return webhook.Delivery{}, nil, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return webhook.Delivery{}, nil, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE tenant_id = $1 AND subscription_id = $2 AND id = $3
+	`, tenantID, subscriptionID, deliveryID)
+	delivery, err := scanDelivery(row)
+	if err != nil {
+		return webhook.Delivery{}, nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT number, COALESCE(status_code, 0), COALESCE(error, ''), duration_ms, attempted_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY id
+	`, deliveryID)
+	if err != nil {
+		return webhook.Delivery{}, nil, err
+	}
+	defer rows.Close()
+
+	var attempts []webhook.Attempt
+	for rows.Next() {
+		var attempt webhook.Attempt
+		var durationMS int64
+		if err := rows.Scan(&attempt.Number, &attempt.StatusCode, &attempt.Error, &durationMS, &attempt.AttemptedAt); err != nil {
+			return webhook.Delivery{}, nil, err
+		}
+		attempt.Duration = time.Duration(durationMS) * time.Millisecond
+		attempts = append(attempts, attempt)
+	}
+	return delivery, attempts, rows.Err()
+}
+
+// RedeliverWebhook puts a delivery back into the pending queue, whatever its
+// current status. Its attempts keep counting, so the attempt log stays
+// numbered in order and a delivery past the retry budget gets a single try.
+func (r *PostgresRepo) RedeliverWebhook(ctx context.Context, subscriptionID, deliveryID string) (webhook.Delivery, error) {
+	ctx, span := r.tracer.Start(ctx, "shipment.repo.RedeliverWebhook")
+	defer span.End()
+
+	tenantID, err := tenant.ID(ctx)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	tx, err := tenant.BeginTx(ctx, r.db)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	defer tx.Rollback()
+
+	var before webhook.Delivery
+	row := tx.QueryRowContext(ctx, `
+		SELECT status, attempts
+		FROM webhook_deliveries
+		WHERE tenant_id = $1 AND subscription_id = $2 AND id = $3
+		FOR UPDATE
+	`, tenantID, subscriptionID, deliveryID)
+	if err := row.Scan(&before.Status, &before.Attempts); err != nil {
+		return webhook.Delivery{}, err
+	}
+
+	row = tx.QueryRowContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = 'PENDING', next_attempt_at = now()
+		WHERE tenant_id = $1 AND subscription_id = $2 AND id = $3
+		RETURNING `+deliveryColumns, tenantID, subscriptionID, deliveryID)
+
+	delivery, err := scanDelivery(row)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	if err := appendAudit(ctx, tx, tenantID, auditWebhookRedelivered, auditEntityDelivery, delivery.ID, deliveryAudit(before), deliveryAudit(delivery)); err != nil {
+		return webhook.Delivery{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return webhook.Delivery{}, err
+	}
+	return delivery, nil
+}
+
+const deliveryColumns = `id::text, subscription_id::text, event_id::text, event_type, status, attempts,
+	next_attempt_at, COALESCE(last_status_code, 0), COALESCE(last_error, ''), created_at, delivered_at`
+
+func scanDelivery(row rowScanner) (webhook.Delivery, error) {
+	var delivery webhook.Delivery
+	var deliveredAt sql.NullTime
+	if err := row.Scan(
+		&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &delivery.Status, &delivery.Attempts,
+		&delivery.NextAttemptAt, &delivery.LastStatusCode, &delivery.LastError, &delivery.CreatedAt, &deliveredAt,
+	); err != nil {
+		return webhook.Delivery{}, err
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return delivery, nil
+}
+
+func scanSubscription(row rowScanner) (webhook.Subscription, error) {
+	var subscription webhook.Subscription
+	var eventTypes []byte
+	if err := row.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, &eventTypes, &subscription.CreatedAt); err != nil {
+		return webhook.Subscription{}, err
+	}
+	if err := json.Unmarshal(eventTypes, &subscription.EventTypes); err != nil {
+		return webhook.Subscription{}, err
+	}
+	return subscription, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/auth"
+)
+
+type action string
+
+const (
+	actionCreate     action = "create"
+	actionRead       action = "read"
+	actionList       action = "list"
+	actionTransition action = "transition"
+	actionCancel     action = "cancel"
+)
+
+// reach is how much of the shipment data an action may touch.
+type reach int
+
+const (
+	reachNone reach = iota
+	// reachOwn limits a merchant to the shipments of their own customer.
+	reachOwn
+	reachAll
+)
+
+// policy is the permission matrix for users with roles. Couriers move
+// parcels along but do not browse or cancel them; auditors only read.
+var policy = map[action]map[auth.Role]reach{
+	actionCreate:     {auth.RoleOperator: reachAll, auth.RoleMerchant: reachOwn},
+	actionRead:       {auth.RoleOperator: reachAll, auth.RoleMerchant: reachOwn, auth.RoleCourier: reachAll, auth.RoleAuditor: reachAll},
+	actionList:       {auth.RoleOperator: reachAll, auth.RoleMerchant: reachOwn, auth.RoleAuditor: reachAll},
+	actionTransition: {auth.RoleOperator: reachAll, auth.RoleCourier: reachAll},
+	actionCancel:     {auth.RoleOperator: reachAll, auth.RoleMerchant: reachOwn},
+}
+
+// grant is what the caller in ctx may do for an action.
+type grant struct {
+	reach reach
+	// customerID is the only customer the caller may touch when reach is
+	// reachOwn.
+	customerID string
+}
+
+// authorize looks up the caller's reach for an action. Calls without a
+// principal come from trusted internal code, and API keys and service
+// tokens are not users; all of them were already limited by scopes at the
+// edge. Any other token needs a role, and the widest reach of its roles wins.
+func authorize(ctx context.Context, act action) (grant, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || principal.Method == auth.MethodAPIKey || principal.HasRole(auth.RoleService) || principal.HasRole(auth.RoleAdmin) {
+		return grant{reach: reachAll}, nil
+	}
+
+	best := reachNone
+	for _, role := range principal.Roles {
+		best = max(best, policy[act][role])
+	}
+	// A merchant token without a customer cannot own anything.
+	if best == reachNone || (best == reachOwn && principal.CustomerID == "") {
+		return grant{}, domain.ErrForbidden
+	}
+	return grant{reach: best, customerID: principal.CustomerID}, nil
+}
+
+// owns reports whether the grant covers a shipment of customerID.
+func (g grant) owns(customerID string) bool {
+	return g.reach == reachAll || g.customerID == customerID
+}
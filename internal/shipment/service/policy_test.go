@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	customerpb "shipment-customer-service/api/proto"
+	domain "shipment-customer-service/internal/domain/shipment"
+	"shipment-customer-service/internal/platform/auth"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ownShipmentID   = "11111111-1111-1111-1111-111111111111"
+	otherShipmentID = "22222222-2222-2222-2222-222222222222"
+	ownCustomerID   = "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
+	otherCustomerID = "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"
+	ownIDN          = "990101123456"
+	otherIDN        = "880202654321"
+)
+
+func newPolicyService() *Service {
+	owners := map[string]string{ownShipmentID: ownCustomerID, otherShipmentID: otherCustomerID}
+	customers := map[string]string{ownIDN: ownCustomerID, otherIDN: otherCustomerID}
+
+	repo := &mockRepo{
+		getFn: func(ctx context.Context, id string) (domain.Shipment, error) {
+			return domain.Shipment{ID: id, Status: domain.StatusCreated, CustomerID: owners[id]}, nil
+		},
+		updateFn: func(ctx context.Context, id, from, to string) (domain.Shipment, error) {
+			return domain.Shipment{ID: id, Status: to, CustomerID: owners[id]}, nil
+		},
+	}
+	client := &mockCustomerClient{
+		upsertFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+			return &customerpb.CustomerResponse{Id: customers[idn]}, nil
+		},
+		getFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+			return &customerpb.CustomerResponse{Id: customers[idn]}, nil
+		},
+	}
+	return New(repo, client)
+}
+
+func TestPolicyMatrix(t *testing.T) {
+	create := func(idn string) func(context.Context, *Service) error {
+		return func(ctx context.Context, s *Service) error {
+			_, err := s.Create(ctx, domain.CreateShipmentInput{Route: "A-B", Price: 1, CustomerIDN: idn})
+			return err
+		}
+	}
+	get := func(id string) func(context.Context, *Service) error {
+		return func(ctx context.Context, s *Service) error {
+			_, err := s.Get(ctx, id)
+			return err
+		}
+	}
+	list := func(customerID string) func(context.Context, *Service) error {
+		return func(ctx context.Context, s *Service) error {
+			_, err := s.List(ctx, domain.ListShipmentsFilter{CustomerID: customerID})
+			return err
+		}
+	}
+	transition := func(id string) func(context.Context, *Service) error {
+		return func(ctx context.Context, s *Service) error {
+			_, err := s.Transition(ctx, id, domain.StatusInTransit)
+			return err
+		}
+	}
+	cancel := func(id string) func(context.Context, *Service) error {
+		return func(ctx context.Context, s *Service) error {
+			_, err := s.Cancel(ctx, id)
+			return err
+		}
+	}
+
+	operations := []struct {
+		name string
+		run  func(context.Context, *Service) error
+	}{
+		{"create own", create(ownIDN)},
+		{"create other", create(otherIDN)},
+		{"get own", get(ownShipmentID)},
+		{"get other", get(otherShipmentID)},
+		{"list all", list("")},
+		{"list other", list(otherCustomerID)},
+		{"transition own", transition(ownShipmentID)},
+		{"transition other", transition(otherShipmentID)},
+		{"cancel own", cancel(ownShipmentID)},
+		{"cancel other", cancel(otherShipmentID)},
+	}
+
+	var (
+		ok        error
+		forbidden = domain.ErrForbidden
+		notFound  = domain.ErrNotFound
+	)
+	user := func(customerID string, roles ...auth.Role) *auth.Principal {
+		return &auth.Principal{ID: "user:u-1", Method: auth.MethodJWT, Roles: roles, CustomerID: customerID}
+	}
+
+	// Each row lists the expected result of the operations above, in order.
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		want      []error
+	}{
+		{name: "internal call", want: []error{ok, ok, ok, ok, ok, ok, ok, ok, ok, ok}},
+		{name: "api key", principal: &auth.Principal{ID: "apikey:k1", Method: auth.MethodAPIKey}, want: []error{ok, ok, ok, ok, ok, ok, ok, ok, ok, ok}},
+		{name: "service token", principal: user("", auth.RoleService), want: []error{ok, ok, ok, ok, ok, ok, ok, ok, ok, ok}},
+		{name: "jwt without roles", principal: user(ownCustomerID), want: []error{forbidden, forbidden, forbidden, forbidden, forbidden, forbidden, forbidden, forbidden, forbidden, forbidden}},
+		{name: "admin", principal: user("", auth.RoleAdmin), want: []error{ok, ok, ok, ok, ok, ok, ok, ok, ok, ok}},
+		{name: "operator", principal: user("", auth.RoleOperator), want: []error{ok, ok, ok, ok, ok, ok, ok, ok, ok, ok}},
+		{name: "merchant", principal: user(ownCustomerID, auth.RoleMerchant), want: []error{ok, forbidden, ok, notFound, ok, forbidden, forbidden, forbidden, ok, notFound}},
+		{name: "merchant without customer", principal: user("", auth.RoleMerchant), want: []error{forbidden, forbidden, forbidden, forbidden, forbidden, forbidden, forbidden, forbidden, forbidden, forbidden}},
+		{name: "courier", principal: user("", auth.RoleCourier), want: []error{forbidden, forbidden, ok, ok, forbidden, forbidden, ok, ok, forbidden, forbidden}},
+		{name: "auditor", principal: user("", auth.RoleAuditor), want: []error{forbidden, forbidden, ok, ok, ok, ok, forbidden, forbidden, forbidden, forbidden}},
+		{name: "merchant and courier", principal: user(ownCustomerID, auth.RoleMerchant, auth.RoleCourier), want: []error{ok, forbidden, ok, ok, ok, forbidden, ok, ok, ok, notFound}},
+	}
+
+	for _, tt := range tests {
+		if len(tt.want) != len(operations) {
+			t.Fatalf("%s: %d expectations for %d operations", tt.name, len(tt.want), len(operations))
+		}
+		for i, op := range operations {
+			t.Run(tt.name+"/"+op.name, func(t *testing.T) {
+				ctx := context.Background()
+				if tt.principal != nil {
+					ctx = auth.NewContext(ctx, *tt.principal)
+				}
+
+				if err := op.run(ctx, newPolicyService()); !errors.Is(err, tt.want[i]) {
+					t.Fatalf("%s error = %v, want %v", op.name, err, tt.want[i])
+				}
+			})
+		}
+	}
+}
+
+func TestListNarrowsMerchantToOwnCustomer(t *testing.T) {
+	var got domain.ListShipmentsFilter
+	svc := New(&mockRepo{listFn: func(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error) {
+		got = filter
+		return nil, nil
+	}}, &mockCustomerClient{})
+	ctx := auth.NewContext(context.Background(), auth.Principal{Roles: []auth.Role{auth.RoleMerchant}, CustomerID: ownCustomerID})
+
+	if _, err := svc.List(ctx, domain.ListShipmentsFilter{}); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got.CustomerID != ownCustomerID {
+		t.Fatalf("List() customer filter = %q, want %q", got.CustomerID, ownCustomerID)
+	}
+}
+
+func TestCreateKeepsMerchantsOutOfDegradedMode(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "down")
+	svc := New(&mockRepo{
+		createPendingFn: func(ctx context.Context, route string, price float64, customerIDN string) (domain.Shipment, error) {
+			t.Fatalf("CreatePendingShipment() called for a merchant")
+			return domain.Shipment{}, nil
+		},
+	}, &mockCustomerClient{getFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+		return nil, unavailable
+	}}, WithDegradedMode())
+	ctx := auth.NewContext(context.Background(), auth.Principal{Roles: []auth.Role{auth.RoleMerchant}, CustomerID: ownCustomerID})
+
+	_, err := svc.Create(ctx, domain.CreateShipmentInput{Route: "A-B", Price: 1, CustomerIDN: ownIDN})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("Create() error = %v, want %v", err, unavailable)
+	}
+}
+
+func TestCreateChecksMerchantOwnershipBeforeUpsert(t *testing.T) {
+	notFound := status.Error(codes.NotFound, "customer not found")
+	tests := []struct {
+		name   string
+		idn    string
+		create func(context.Context, *Service, string) error
+		want   error
+	}{
+		{name: "own customer", idn: ownIDN, create: createOne},
+		{name: "other customer", idn: otherIDN, create: createOne, want: domain.ErrForbidden},
+		{name: "unknown customer", idn: "770303111111", create: createOne, want: domain.ErrForbidden},
+		{name: "batch with other customer", idn: otherIDN, create: createBatch, want: domain.ErrForbidden},
+		{name: "batch with unknown customer", idn: "770303111111", create: createBatch, want: domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customers := map[string]string{ownIDN: ownCustomerID, otherIDN: otherCustomerID}
+			svc := New(&mockRepo{}, &mockCustomerClient{
+				upsertFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+					t.Fatalf("UpsertCustomer(%s) called for a merchant", idn)
+					return nil, nil
+				},
+				getFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+					id, ok := customers[idn]
+					if !ok {
+						return nil, notFound
+					}
+					return &customerpb.CustomerResponse{Id: id, Idn: idn}, nil
+				},
+			})
+			ctx := auth.NewContext(context.Background(), auth.Principal{Roles: []auth.Role{auth.RoleMerchant}, CustomerID: ownCustomerID})
+
+			if err := tt.create(ctx, svc, tt.idn); !errors.Is(err, tt.want) {
+				t.Fatalf("create error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func createOne(ctx context.Context, s *Service, idn string) error {
+	_, err := s.Create(ctx, domain.CreateShipmentInput{Route: "A-B", Price: 1, CustomerIDN: idn})
+	return err
+}
+
+func createBatch(ctx context.Context, s *Service, idn string) error {
+	_, err := s.CreateBatch(ctx, []domain.CreateShipmentInput{
+		{Route: "A-B", Price: 1, CustomerIDN: ownIDN},
+		{Route: "A-C", Price: 2, CustomerIDN: idn},
+	})
+	return err
+}
@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	customerpb "shipment-customer-service/api/proto"
 	domain "shipment-customer-service/internal/domain/shipment"
 	"shipment-customer-service/internal/shipment/grpc"
 )
@@ -14,40 +17,148 @@ import (
 type ShipmentRepository interface {
 	CreateShipment(ctx context.Context, route string, price float64, customerID string) (domain.Shipment, error)
 	GetShipment(ctx context.Context, id string) (domain.Shipment, error)
+	UpdateShipmentStatus(ctx context.Context, id, from, to string) (domain.Shipment, error)
+	CreatePendingShipment(ctx context.Context, route string, price float64, customerIDN string) (domain.Shipment, error)
+	CreateShipments(ctx context.Context, shipments []domain.Shipment) ([]domain.Shipment, error)
+	ListShipments(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error)
 }
 
 type Service struct {
 	repo           ShipmentRepository
 	customerClient grpc.CustomerClient
+	degraded       bool
 }
 
-func New(repository ShipmentRepository, customerClient grpc.CustomerClient) *Service {
-	return &Service{repo: repository, customerClient: customerClient}
+type Option func(*Service)
+
+// WithDegradedMode makes Create accept shipments while the customer service
+// is unavailable. They are stored as PENDING_CUSTOMER and resolved later by
+// the reconciler.
+func WithDegradedMode() Option {
+	return func(s *Service) {
+		s.degraded = true
+	}
+}
+
+func New(repository ShipmentRepository, customerClient grpc.CustomerClient, opts ...Option) *Service {
+	s := &Service{repo: repository, customerClient: customerClient}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Service) Create(ctx context.Context, input domain.CreateShipmentInput) (domain.Shipment, error) {
-	route := strings.TrimSpace(input.Route)
-	if route == "" {
-		return domain.Shipment{}, domain.ErrInvalidRoute
+	g, err := authorize(ctx, actionCreate)
+	if err != nil {
+		return domain.Shipment{}, err
 	}
-	if input.Price <= 0 {
-		return domain.Shipment{}, domain.ErrInvalidPrice
+	input = input.Normalize()
+	if err := input.Validate(); err != nil {
+		return domain.Shipment{}, err
 	}
 
-	idn := strings.TrimSpace(input.CustomerIDN)
-	if !domain.IsValidIDN(idn) {
-		return domain.Shipment{}, domain.ErrInvalidIDN
+	customer, err := s.resolveCustomer(ctx, g, input.CustomerIDN)
+	if err != nil && s.acceptPending(ctx, g, err) {
+		return s.repo.CreatePendingShipment(ctx, input.Route, input.Price, input.CustomerIDN)
 	}
-
-	customer, err := s.customerClient.UpsertCustomer(ctx, idn)
 	if err != nil {
 		return domain.Shipment{}, err
 	}
 
-	return s.repo.CreateShipment(ctx, route, input.Price, customer.GetId())
+	return s.repo.CreateShipment(ctx, input.Route, input.Price, customer.GetId())
+}
+
+// CreateBatch creates all shipments or none of them. Every input is validated
+// before the customer service is called, and each distinct IDN is resolved
+// once. Customers are resolved before the shipments are written and are not
+// rolled back: a batch that fails afterwards may leave customers it upserted,
+// which a retry finds again.
+func (s *Service) CreateBatch(ctx context.Context, inputs []domain.CreateShipmentInput) ([]domain.Shipment, error) {
+	g, err := authorize(ctx, actionCreate)
+	if err != nil {
+		return nil, err
+	}
+	normalized := make([]domain.CreateShipmentInput, len(inputs))
+	for i, input := range inputs {
+		normalized[i] = input.Normalize()
+	}
+	if err := domain.ValidateBatch(normalized); err != nil {
+		return nil, err
+	}
+
+	// An empty customer ID marks an IDN left pending in degraded mode.
+	customerIDs := make(map[string]string)
+	shipments := make([]domain.Shipment, len(normalized))
+	for i, input := range normalized {
+		customerID, ok := customerIDs[input.CustomerIDN]
+		if !ok {
+			customer, err := s.resolveCustomer(ctx, g, input.CustomerIDN)
+			if err != nil && !s.acceptPending(ctx, g, err) {
+				return nil, err
+			}
+			customerID = customer.GetId()
+			customerIDs[input.CustomerIDN] = customerID
+		}
+
+		shipments[i] = domain.Shipment{Route: input.Route, Price: input.Price, Status: domain.StatusCreated, CustomerID: customerID}
+		if customerID == "" {
+			shipments[i].Status = domain.StatusPendingCustomer
+			shipments[i].CustomerIDN = input.CustomerIDN
+		}
+	}
+
+	return s.repo.CreateShipments(ctx, shipments)
+}
+
+// resolveCustomer returns the customer of idn. Callers limited to their own
+// customer only look it up, and are turned away before anything is written
+// unless it is theirs: upserting first would let them create customers for
+// any IDN. Their own customer exists already, so a missing one is not theirs.
+func (s *Service) resolveCustomer(ctx context.Context, g grant, idn string) (*customerpb.CustomerResponse, error) {
+	if g.reach == reachAll {
+		return s.customerClient.UpsertCustomer(ctx, idn)
+	}
+	customer, err := s.customerClient.GetCustomer(ctx, idn)
+	if status.Code(err) == codes.NotFound {
+		return nil, domain.ErrForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !g.owns(customer.GetId()) {
+		return nil, domain.ErrForbidden
+	}
+	return customer, nil
+}
+
+// acceptPending reports whether a failed customer upsert should leave the
+// shipment pending. Ownership cannot be checked without the customer, so
+// callers limited to their own customer do not get degraded mode.
+func (s *Service) acceptPending(ctx context.Context, g grant, err error) bool {
+	return s.degraded && g.reach == reachAll && customerUnavailable(err) && ctx.Err() == nil
+}
+
+// Ready reports whether shipments can currently be created. It fails while
+// the customer client refuses calls, e.g. because its circuit is open.
+func (s *Service) Ready() error {
+	if checker, ok := s.customerClient.(interface{ Ready() error }); ok {
+		return checker.Ready()
+	}
+	return nil
 }
 
+// Get returns a shipment the caller may read. Shipments of other customers
+// are reported as not found, so their IDs cannot be probed.
 func (s *Service) Get(ctx context.Context, id string) (domain.Shipment, error) {
+	g, err := authorize(ctx, actionRead)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	return s.getOwned(ctx, g, id)
+}
+
+func (s *Service) getOwned(ctx context.Context, g grant, id string) (domain.Shipment, error) {
 	if _, err := uuid.Parse(id); err != nil {
 		return domain.Shipment{}, domain.ErrInvalidShipmentID
 	}
@@ -59,6 +170,106 @@ func (s *Service) Get(ctx context.Context, id string) (domain.Shipment, error) {
 	if err != nil {
 		return domain.Shipment{}, err
 	}
+	if !g.owns(shipment.CustomerID) {
+		return domain.Shipment{}, domain.ErrNotFound
+	}
 
 	return shipment, nil
 }
+
+// List returns shipments matching filter. Merchants only list their own
+// customer's shipments; asking for another customer is forbidden.
+func (s *Service) List(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error) {
+	customerID, err := s.AuthorizeFeed(ctx, filter.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	filter.CustomerID = customerID
+
+	if filter.CustomerID != "" {
+		if _, err := uuid.Parse(filter.CustomerID); err != nil {
+			return nil, domain.ErrInvalidCustomerID
+		}
+	}
+	if filter.After != "" {
+		if _, err := uuid.Parse(filter.After); err != nil {
+			return nil, domain.ErrInvalidPageToken
+		}
+	}
+	filter.Status = strings.ToUpper(strings.TrimSpace(filter.Status))
+	if filter.Status != "" && !domain.IsValidStatus(filter.Status) {
+		return nil, domain.ErrInvalidStatus
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = domain.DefaultListLimit
+	}
+	filter.Limit = min(filter.Limit, domain.MaxListLimit)
+
+	return s.repo.ListShipments(ctx, filter)
+}
+
+// AuthorizeFeed checks that the caller may follow the shipments of
+// customerID, "" meaning all customers, and returns the customer filter to
+// apply: merchants are narrowed to their own customer.
+func (s *Service) AuthorizeFeed(ctx context.Context, customerID string) (string, error) {
+	g, err := authorize(ctx, actionList)
+	if err != nil {
+		return "", err
+	}
+	if g.reach == reachOwn {
+		if customerID != "" && customerID != g.customerID {
+			return "", domain.ErrForbidden
+		}
+		return g.customerID, nil
+	}
+	return customerID, nil
+}
+
+// Transition moves a shipment to status if the lifecycle allows it. A
+// concurrent change between the read and the write yields ErrStatusConflict.
+func (s *Service) Transition(ctx context.Context, id, status string) (domain.Shipment, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	if !domain.IsValidStatus(status) {
+		return domain.Shipment{}, domain.ErrInvalidStatus
+	}
+
+	act := actionTransition
+	if status == domain.StatusCancelled {
+		act = actionCancel
+	}
+	g, err := authorize(ctx, act)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+
+	current, err := s.getOwned(ctx, g, id)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if !domain.CanTransition(current.Status, status) {
+		return domain.Shipment{}, domain.ErrInvalidTransition
+	}
+
+	shipment, err := s.repo.UpdateShipmentStatus(ctx, id, current.Status, status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Shipment{}, domain.ErrStatusConflict
+	}
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+
+	return shipment, nil
+}
+
+func customerUnavailable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Service) Cancel(ctx context.Context, id string) (domain.Shipment, error) {
+	return s.Transition(ctx, id, domain.StatusCancelled)
+}
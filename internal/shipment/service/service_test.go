@@ -4,16 +4,24 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
 	customerpb "shipment-customer-service/api/proto"
 	domain "shipment-customer-service/internal/domain/shipment"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type mockRepo struct {
-	createFn func(ctx context.Context, route string, price float64, customerID string) (domain.Shipment, error)
-	getFn    func(ctx context.Context, id string) (domain.Shipment, error)
+	createFn        func(ctx context.Context, route string, price float64, customerID string) (domain.Shipment, error)
+	getFn           func(ctx context.Context, id string) (domain.Shipment, error)
+	updateFn        func(ctx context.Context, id, from, to string) (domain.Shipment, error)
+	createPendingFn func(ctx context.Context, route string, price float64, customerIDN string) (domain.Shipment, error)
+	listFn          func(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error)
+	createBatchFn   func(ctx context.Context, shipments []domain.Shipment) ([]domain.Shipment, error)
 }
 
 func (m *mockRepo) CreateShipment(ctx context.Context, route string, price float64, customerID string) (domain.Shipment, error) {
@@ -30,8 +38,37 @@ func (m *mockRepo) GetShipment(ctx context.Context, id string) (domain.Shipment,
 	return m.getFn(ctx, id)
 }
 
+func (m *mockRepo) UpdateShipmentStatus(ctx context.Context, id, from, to string) (domain.Shipment, error) {
+	if m.updateFn == nil {
+		return domain.Shipment{}, nil
+	}
+	return m.updateFn(ctx, id, from, to)
+}
+
+func (m *mockRepo) CreatePendingShipment(ctx context.Context, route string, price float64, customerIDN string) (domain.Shipment, error) {
+	if m.createPendingFn == nil {
+		return domain.Shipment{}, nil
+	}
+	return m.createPendingFn(ctx, route, price, customerIDN)
+}
+
+func (m *mockRepo) ListShipments(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error) {
+	if m.listFn == nil {
+		return nil, nil
+	}
+	return m.listFn(ctx, filter)
+}
+
+func (m *mockRepo) CreateShipments(ctx context.Context, shipments []domain.Shipment) ([]domain.Shipment, error) {
+	if m.createBatchFn == nil {
+		return shipments, nil
+	}
+	return m.createBatchFn(ctx, shipments)
+}
+
 type mockCustomerClient struct {
 	upsertFn func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error)
+	getFn    func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error)
 }
 
 func (m *mockCustomerClient) UpsertCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
@@ -41,6 +78,13 @@ func (m *mockCustomerClient) UpsertCustomer(ctx context.Context, idn string) (*c
 	return m.upsertFn(ctx, idn)
 }
 
+func (m *mockCustomerClient) GetCustomer(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+	if m.getFn == nil {
+		return nil, nil
+	}
+	return m.getFn(ctx, idn)
+}
+
 func TestCreateValidation(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -50,6 +94,7 @@ func TestCreateValidation(t *testing.T) {
 		{name: "invalid route", input: domain.CreateShipmentInput{Route: "   ", Price: 1, CustomerIDN: "990101123456"}, err: domain.ErrInvalidRoute},
 		{name: "invalid price", input: domain.CreateShipmentInput{Route: "A-B", Price: 0, CustomerIDN: "990101123456"}, err: domain.ErrInvalidPrice},
 		{name: "invalid idn", input: domain.CreateShipmentInput{Route: "A-B", Price: 1, CustomerIDN: "123"}, err: domain.ErrInvalidIDN},
+		{name: "all fields", input: domain.CreateShipmentInput{Price: -1, CustomerIDN: "abc"}, err: domain.ErrInvalidPrice},
 	}
 
 	for _, tc := range tests {
@@ -64,6 +109,28 @@ func TestCreateValidation(t *testing.T) {
 	}
 }
 
+func TestCreateReportsEveryInvalidField(t *testing.T) {
+	svc := New(&mockRepo{}, &mockCustomerClient{upsertFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+		t.Fatalf("UpsertCustomer() called for an invalid request")
+		return nil, nil
+	}})
+
+	_, err := svc.Create(context.Background(), domain.CreateShipmentInput{Route: " ", Price: 0, CustomerIDN: "123"})
+
+	var validation *domain.ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Create() error = %v, want *ValidationError", err)
+	}
+	want := []domain.FieldError{
+		{Pointer: "/route", Code: domain.CodeInvalidRoute, Detail: domain.ErrInvalidRoute.Error()},
+		{Pointer: "/price", Code: domain.CodeInvalidPrice, Detail: domain.ErrInvalidPrice.Error()},
+		{Pointer: "/customer/idn", Code: domain.CodeInvalidIDN, Detail: domain.ErrInvalidIDN.Error()},
+	}
+	if !slices.Equal(validation.Fields, want) {
+		t.Fatalf("Create() fields = %+v, want %+v", validation.Fields, want)
+	}
+}
+
 func TestCreateSuccess(t *testing.T) {
 	var gotRoute string
 	var gotPrice float64
@@ -127,6 +194,182 @@ func TestCreateCustomerError(t *testing.T) {
 	}
 }
 
+func TestCreateDegradedMode(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "customer service down")
+	invalid := status.Error(codes.InvalidArgument, "bad idn")
+
+	tests := []struct {
+		name        string
+		opts        []Option
+		upsertErr   error
+		wantPending bool
+		wantErr     error
+	}{
+		{name: "disabled", upsertErr: unavailable, wantErr: unavailable},
+		{name: "unavailable", opts: []Option{WithDegradedMode()}, upsertErr: unavailable, wantPending: true},
+		{name: "deadline exceeded", opts: []Option{WithDegradedMode()}, upsertErr: status.Error(codes.DeadlineExceeded, "slow"), wantPending: true},
+		{name: "other errors still fail", opts: []Option{WithDegradedMode()}, upsertErr: invalid, wantErr: invalid},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotIDN string
+			svc := New(
+				&mockRepo{createPendingFn: func(ctx context.Context, route string, price float64, customerIDN string) (domain.Shipment, error) {
+					gotIDN = customerIDN
+					return domain.Shipment{ID: "s1", Status: domain.StatusPendingCustomer, CustomerIDN: customerIDN}, nil
+				}},
+				&mockCustomerClient{upsertFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+					return nil, tc.upsertErr
+				}},
+				tc.opts...,
+			)
+
+			got, err := svc.Create(context.Background(), domain.CreateShipmentInput{Route: "A-B", Price: 1, CustomerIDN: "990101123456"})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantPending && (got.Status != domain.StatusPendingCustomer || gotIDN != "990101123456") {
+				t.Fatalf("Create() = %+v with idn %q, want pending shipment", got, gotIDN)
+			}
+			if !tc.wantPending && gotIDN != "" {
+				t.Fatalf("Create() stored a pending shipment")
+			}
+		})
+	}
+}
+
+func TestCreateBatchValidation(t *testing.T) {
+	valid := domain.CreateShipmentInput{Route: "A-B", Price: 1, CustomerIDN: "990101123456"}
+
+	tests := []struct {
+		name     string
+		inputs   []domain.CreateShipmentInput
+		pointers []string
+	}{
+		{name: "empty", pointers: []string{"/shipments"}},
+		{name: "too large", inputs: slices.Repeat([]domain.CreateShipmentInput{valid}, domain.MaxBatchSize+1), pointers: []string{"/shipments"}},
+		{
+			name:     "invalid items",
+			inputs:   []domain.CreateShipmentInput{valid, {Route: "A-B", CustomerIDN: "990101123456"}, {Price: 1, CustomerIDN: "1"}},
+			pointers: []string{"/shipments/1/price", "/shipments/2/route", "/shipments/2/customer/idn"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := New(&mockRepo{}, &mockCustomerClient{upsertFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+				t.Fatalf("UpsertCustomer() called for an invalid batch")
+				return nil, nil
+			}})
+
+			_, err := svc.CreateBatch(context.Background(), tc.inputs)
+
+			var validation *domain.ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("CreateBatch() error = %v, want *ValidationError", err)
+			}
+			var pointers []string
+			for _, field := range validation.Fields {
+				pointers = append(pointers, field.Pointer)
+			}
+			if !slices.Equal(pointers, tc.pointers) {
+				t.Fatalf("CreateBatch() pointers = %v, want %v", pointers, tc.pointers)
+			}
+		})
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	var upserted []string
+	svc := New(
+		&mockRepo{},
+		&mockCustomerClient{upsertFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+			upserted = append(upserted, idn)
+			if idn == "990101000002" {
+				return nil, status.Error(codes.Unavailable, "customer service down")
+			}
+			return &customerpb.CustomerResponse{Id: "c-" + idn}, nil
+		}},
+		WithDegradedMode(),
+	)
+
+	got, err := svc.CreateBatch(context.Background(), []domain.CreateShipmentInput{
+		{Route: " A-B ", Price: 1, CustomerIDN: "990101000001"},
+		{Route: "B-C", Price: 2, CustomerIDN: "990101000001"},
+		{Route: "C-D", Price: 3, CustomerIDN: "990101000002"},
+	})
+	if err != nil {
+		t.Fatalf("CreateBatch() error = %v", err)
+	}
+
+	if want := []string{"990101000001", "990101000002"}; !slices.Equal(upserted, want) {
+		t.Fatalf("CreateBatch() upserted %v, want %v", upserted, want)
+	}
+	want := []domain.Shipment{
+		{Route: "A-B", Price: 1, Status: domain.StatusCreated, CustomerID: "c-990101000001"},
+		{Route: "B-C", Price: 2, Status: domain.StatusCreated, CustomerID: "c-990101000001"},
+		{Route: "C-D", Price: 3, Status: domain.StatusPendingCustomer, CustomerIDN: "990101000002"},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("CreateBatch() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCreateBatchCustomerError(t *testing.T) {
+	wantErr := status.Error(codes.InvalidArgument, "bad idn")
+	svc := New(
+		&mockRepo{createBatchFn: func(ctx context.Context, shipments []domain.Shipment) ([]domain.Shipment, error) {
+			t.Fatalf("CreateShipments() called after a customer error")
+			return nil, nil
+		}},
+		&mockCustomerClient{upsertFn: func(ctx context.Context, idn string) (*customerpb.CustomerResponse, error) {
+			return nil, wantErr
+		}},
+		WithDegradedMode(),
+	)
+
+	_, err := svc.CreateBatch(context.Background(), []domain.CreateShipmentInput{{Route: "A-B", Price: 1, CustomerIDN: "990101123456"}})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("CreateBatch() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestList(t *testing.T) {
+	customerID := "22222222-2222-2222-2222-222222222222"
+
+	tests := []struct {
+		name       string
+		filter     domain.ListShipmentsFilter
+		wantFilter domain.ListShipmentsFilter
+		err        error
+	}{
+		{name: "defaults", filter: domain.ListShipmentsFilter{}, wantFilter: domain.ListShipmentsFilter{Limit: 50}},
+		{name: "normalised", filter: domain.ListShipmentsFilter{CustomerID: customerID, Status: " in_transit ", Limit: 500}, wantFilter: domain.ListShipmentsFilter{CustomerID: customerID, Status: domain.StatusInTransit, Limit: 100}},
+		{name: "invalid customer", filter: domain.ListShipmentsFilter{CustomerID: "nope"}, err: domain.ErrInvalidCustomerID},
+		{name: "invalid status", filter: domain.ListShipmentsFilter{Status: "LOST"}, err: domain.ErrInvalidStatus},
+		{name: "invalid page token", filter: domain.ListShipmentsFilter{After: "nope"}, err: domain.ErrInvalidPageToken},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotFilter domain.ListShipmentsFilter
+			svc := New(&mockRepo{listFn: func(ctx context.Context, filter domain.ListShipmentsFilter) ([]domain.Shipment, error) {
+				gotFilter = filter
+				return nil, nil
+			}}, &mockCustomerClient{})
+
+			_, err := svc.List(context.Background(), tc.filter)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("List() error = %v, want %v", err, tc.err)
+			}
+			if gotFilter != tc.wantFilter {
+				t.Fatalf("List() filter passed to repo = %+v, want %+v", gotFilter, tc.wantFilter)
+			}
+		})
+	}
+}
+
 func TestGet(t *testing.T) {
 	now := time.Now().UTC()
 	want := domain.Shipment{ID: "11111111-1111-1111-1111-111111111111", Route: "A-B", Price: 1, Status: "CREATED", CustomerID: "c1", CreatedAt: now}
@@ -173,3 +416,50 @@ func TestGet(t *testing.T) {
 		}
 	})
 }
+
+func TestTransition(t *testing.T) {
+	const id = "11111111-1111-1111-1111-111111111111"
+
+	tests := []struct {
+		name      string
+		current   string
+		target    string
+		updateErr error
+		err       error
+	}{
+		{name: "invalid status", current: domain.StatusCreated, target: "LOST", err: domain.ErrInvalidStatus},
+		{name: "not allowed", current: domain.StatusDelivered, target: domain.StatusCancelled, err: domain.ErrInvalidTransition},
+		{name: "concurrent change", current: domain.StatusCreated, target: domain.StatusInTransit, updateErr: sql.ErrNoRows, err: domain.ErrStatusConflict},
+		{name: "success", current: domain.StatusCreated, target: "in_transit"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var gotFrom, gotTo string
+			svc := New(&mockRepo{
+				getFn: func(ctx context.Context, id string) (domain.Shipment, error) {
+					return domain.Shipment{ID: id, Status: tc.current}, nil
+				},
+				updateFn: func(ctx context.Context, id, from, to string) (domain.Shipment, error) {
+					gotFrom, gotTo = from, to
+					return domain.Shipment{ID: id, Status: to}, tc.updateErr
+				},
+			}, &mockCustomerClient{})
+
+			got, err := svc.Transition(context.Background(), id, tc.target)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Transition() error = %v, want %v", err, tc.err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if gotFrom != domain.StatusCreated || gotTo != domain.StatusInTransit {
+				t.Fatalf("Transition() passed %s -> %s to repo", gotFrom, gotTo)
+			}
+			if got.Status != domain.StatusInTransit {
+				t.Fatalf("Transition() status = %s, want %s", got.Status, domain.StatusInTransit)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package stream
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+)
+
+const (
+	subscriberBuffer = 64
+	replayPageSize   = 500
+	reconnectDelay   = 2 * time.Second
+)
+
+type Store interface {
+	ListenShipmentEvents(ctx context.Context, events chan<- domain.Event) error
+	ListEventsSince(ctx context.Context, seq int64, shipmentID, customerID string, limit int) ([]domain.Event, error)
+}
+
+// Filter selects the events a subscriber receives. Events of other tenants
+// never match; the other fields match all when empty.
+type Filter struct {
+	TenantID   string
+	ShipmentID string
+	CustomerID string
+}
+
+func (f Filter) Match(event domain.Event) bool {
+	if event.Data.TenantID != f.TenantID {
+		return false
+	}
+	if f.ShipmentID != "" && event.ShipmentID != f.ShipmentID {
+		return false
+	}
+	if f.CustomerID != "" && event.Data.CustomerID != f.CustomerID {
+		return false
+	}
+	return true
+}
+
+// Subscription receives live events on C. C is closed when the subscriber
+// falls too far behind or the broker stops; clients should reconnect and
+// resume from the last event they saw.
+type Subscription struct {
+	C      <-chan domain.Event
+	ch     chan domain.Event
+	filter Filter
+	broker *Broker
+}
+
+func (s *Subscription) Close() {
+	s.broker.remove(s)
+}
+
+// Broker fans shipment events from a single Postgres LISTEN connection out to
+// any number of in-process subscribers.
+type Broker struct {
+	store  Store
+	logger *slog.Logger
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func NewBroker(store Store, logger *slog.Logger) *Broker {
+	return &Broker{store: store, logger: logger, subs: make(map[*Subscription]struct{})}
+}
+
+// Run listens for events until ctx is cancelled, reconnecting on failure.
+func (b *Broker) Run(ctx context.Context) {
+	events := make(chan domain.Event, subscriberBuffer)
+	go func() {
+		for {
+			err := b.store.ListenShipmentEvents(ctx, events)
+			if ctx.Err() != nil {
+				return
+			}
+			b.logger.Warn("shipment_events_listen_failed", slog.String("error", err.Error()))
+			// Subscribers may have missed events while disconnected; closing
+			// them makes clients resume through the replay path.
+			b.closeAll()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reconnectDelay):
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.closeAll()
+			return
+		case event := <-events:
+			b.broadcast(event)
+		}
+	}
+}
+
+func (b *Broker) Subscribe(filter Filter) *Subscription {
+	ch := make(chan domain.Event, subscriberBuffer)
+	sub := &Subscription{C: ch, ch: ch, filter: filter, broker: b}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Replay passes the stored events after seq that match filter to send, oldest
+// first, reading them page by page until the backlog is exhausted. It returns
+// the sequence number of the last event sent, or seq. The store reads the
+// tenant from ctx, which must be the one of filter.
+func (b *Broker) Replay(ctx context.Context, seq int64, filter Filter, send func(domain.Event) error) (int64, error) {
+	for {
+		events, err := b.store.ListEventsSince(ctx, seq, filter.ShipmentID, filter.CustomerID, replayPageSize)
+		if err != nil {
+			return seq, err
+		}
+		for _, event := range events {
+			if err := send(event); err != nil {
+				return seq, err
+			}
+			seq = event.Seq
+		}
+		if len(events) < replayPageSize {
+			return seq, nil
+		}
+	}
+}
+
+func (b *Broker) broadcast(event domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
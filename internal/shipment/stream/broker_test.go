@@ -0,0 +1,131 @@
+package stream
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	domain "shipment-customer-service/internal/domain/shipment"
+)
+
+func TestBroadcastFilters(t *testing.T) {
+	broker := NewBroker(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	byShipment := broker.Subscribe(Filter{TenantID: "brand-a", ShipmentID: "s1"})
+	byCustomer := broker.Subscribe(Filter{TenantID: "brand-a", CustomerID: "c2"})
+	all := broker.Subscribe(Filter{TenantID: "brand-a"})
+	otherTenant := broker.Subscribe(Filter{TenantID: "brand-b"})
+
+	broker.broadcast(domain.Event{Seq: 1, ShipmentID: "s1", Data: domain.EventData{TenantID: "brand-a", CustomerID: "c1"}})
+	broker.broadcast(domain.Event{Seq: 2, ShipmentID: "s2", Data: domain.EventData{TenantID: "brand-a", CustomerID: "c2"}})
+
+	if got := len(byShipment.C); got != 1 {
+		t.Fatalf("shipment subscriber got %d events, want 1", got)
+	}
+	if got := len(byCustomer.C); got != 1 {
+		t.Fatalf("customer subscriber got %d events, want 1", got)
+	}
+	if got := len(all.C); got != 2 {
+		t.Fatalf("unfiltered subscriber got %d events, want 2", got)
+	}
+	if got := len(otherTenant.C); got != 0 {
+		t.Fatalf("subscriber of another tenant got %d events, want 0", got)
+	}
+	if event := <-byCustomer.C; event.Seq != 2 {
+		t.Fatalf("customer subscriber got seq %d, want 2", event.Seq)
+	}
+}
+
+func TestSlowSubscriberIsClosed(t *testing.T) {
+	broker := NewBroker(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	sub := broker.Subscribe(Filter{})
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		broker.broadcast(domain.Event{Seq: int64(i + 1)})
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Fatalf("received %d events before close, want %d", received, subscriberBuffer)
+	}
+
+	// Closing an already dropped subscription must not panic.
+	sub.Close()
+}
+
+type pagedStore struct {
+	events []domain.Event
+	calls  int
+}
+
+func (s *pagedStore) ListenShipmentEvents(ctx context.Context, events chan<- domain.Event) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (s *pagedStore) ListEventsSince(ctx context.Context, seq int64, shipmentID, customerID string, limit int) ([]domain.Event, error) {
+	s.calls++
+	var page []domain.Event
+	for _, event := range s.events {
+		if event.Seq > seq && len(page) < limit {
+			page = append(page, event)
+		}
+	}
+	return page, nil
+}
+
+func TestReplayPagesThroughBacklog(t *testing.T) {
+	tests := []struct {
+		name      string
+		stored    int
+		from      int64
+		wantSent  int
+		wantCalls int
+	}{
+		{name: "empty", stored: 0, wantSent: 0, wantCalls: 1},
+		{name: "one page", stored: 10, from: 3, wantSent: 7, wantCalls: 1},
+		{name: "exact page", stored: replayPageSize, wantSent: replayPageSize, wantCalls: 2},
+		{name: "several pages", stored: 2*replayPageSize + 7, wantSent: 2*replayPageSize + 7, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &pagedStore{}
+			for i := 1; i <= tt.stored; i++ {
+				store.events = append(store.events, domain.Event{Seq: int64(i)})
+			}
+			broker := NewBroker(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			var sent []int64
+			last, err := broker.Replay(context.Background(), tt.from, Filter{}, func(event domain.Event) error {
+				sent = append(sent, event.Seq)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Replay() error = %v", err)
+			}
+
+			if len(sent) != tt.wantSent {
+				t.Fatalf("Replay() sent %d events, want %d", len(sent), tt.wantSent)
+			}
+			for i, seq := range sent {
+				if seq != tt.from+int64(i)+1 {
+					t.Fatalf("Replay() event %d has seq %d, want %d", i, seq, tt.from+int64(i)+1)
+				}
+			}
+			wantLast := tt.from
+			if tt.wantSent > 0 {
+				wantLast = sent[len(sent)-1]
+			}
+			if last != wantLast {
+				t.Fatalf("Replay() last = %d, want %d", last, wantLast)
+			}
+			if store.calls != tt.wantCalls {
+				t.Fatalf("Replay() store calls = %d, want %d", store.calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	shipment "shipment-customer-service/internal/domain/shipment"
+	domain "shipment-customer-service/internal/domain/webhook"
+)
+
+type DeliveryStore interface {
+	EnqueueWebhookDeliveries(ctx context.Context, event shipment.Event) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.Delivery, error)
+	RecordWebhookAttempt(ctx context.Context, deliveryID string, attempt domain.Attempt, status string, nextAttemptAt time.Time) error
+}
+
+// Publisher fans outbox events out into per-subscription deliveries. It is
+// an outbox.Publisher; the actual HTTP calls are made by the Dispatcher.
+type Publisher struct {
+	store DeliveryStore
+}
+
+func NewPublisher(store DeliveryStore) *Publisher {
+	return &Publisher{store: store}
+}
+
+func (p *Publisher) Publish(ctx context.Context, event shipment.Event) error {
+	return p.store.EnqueueWebhookDeliveries(ctx, event)
+}
+
+// Dispatcher sends pending deliveries, retrying failures with exponential
+// backoff and dead-lettering them after maxAttempts. A claimed batch is sent
+// by up to workers deliveries at a time; batchSize, workers and the client
+// timeout are chosen so that a batch of receivers which all hang is still
+// done well within the lease, before another dispatcher may claim it again.
+type Dispatcher struct {
+	store       DeliveryStore
+	client      *http.Client
+	logger      *slog.Logger
+	interval    time.Duration
+	batchSize   int
+	workers     int
+	lease       time.Duration
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+func NewDispatcher(store DeliveryStore, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		store:       store,
+		client:      newClient(10 * time.Second),
+		logger:      logger,
+		interval:    time.Second,
+		batchSize:   20,
+		workers:     10,
+		lease:       time.Minute,
+		maxAttempts: 8,
+		baseBackoff: 30 * time.Second,
+		maxBackoff:  6 * time.Hour,
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if d.dispatch(ctx) == d.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) int {
+	deliveries, err := d.store.ClaimWebhookDeliveries(ctx, d.batchSize, d.lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.logger.Error("webhook_claim_failed", slog.String("error", err.Error()))
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, d.workers)
+	for _, delivery := range deliveries {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery domain.Delivery) {
+	attempt := d.send(ctx, delivery)
+
+	status := domain.DeliveryDelivered
+	next := attempt.AttemptedAt
+	if attempt.Error != "" {
+		status = domain.DeliveryPending
+		next = attempt.AttemptedAt.Add(Backoff(attempt.Number, d.baseBackoff, d.maxBackoff))
+		if attempt.Number >= d.maxAttempts {
+			status = domain.DeliveryDead
+		}
+		d.logger.Warn(
+			"webhook_delivery_failed",
+			slog.String("delivery_id", delivery.ID),
+			slog.Int("attempt", attempt.Number),
+			slog.String("status", status),
+			slog.String("error", attempt.Error),
+		)
+	}
+
+	if err := d.store.RecordWebhookAttempt(ctx, delivery.ID, attempt, status, next); err != nil {
+		d.logger.Error("webhook_record_failed", slog.String("delivery_id", delivery.ID), slog.String("error", err.Error()))
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery domain.Delivery) (attempt domain.Attempt) {
+	attempt = domain.Attempt{Number: delivery.Attempts + 1, AttemptedAt: time.Now()}
+	defer func() { attempt.Duration = time.Since(attempt.AttemptedAt) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, attempt.AttemptedAt.UTC().Format(time.RFC3339))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, attempt.AttemptedAt, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = resp.Status
+	}
+	return attempt
+}
+
+// Backoff returns the delay before the attempt following attempt number n:
+// base doubled for every failed attempt, capped at max.
+func Backoff(n int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < n; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -0,0 +1,250 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	shipment "shipment-customer-service/internal/domain/shipment"
+	domain "shipment-customer-service/internal/domain/webhook"
+)
+
+type recordedAttempt struct {
+	attempt domain.Attempt
+	status  string
+	next    time.Time
+}
+
+type fakeStore struct {
+	mu         sync.Mutex
+	deliveries []domain.Delivery
+	recorded   []recordedAttempt
+}
+
+func (f *fakeStore) EnqueueWebhookDeliveries(ctx context.Context, event shipment.Event) error {
+	return nil
+}
+
+func (f *fakeStore) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.Delivery, error) {
+	deliveries := f.deliveries
+	f.deliveries = nil
+	return deliveries, nil
+}
+
+func (f *fakeStore) RecordWebhookAttempt(ctx context.Context, deliveryID string, attempt domain.Attempt, status string, next time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recorded = append(f.recorded, recordedAttempt{attempt: attempt, status: status, next: next})
+	return nil
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"ShipmentCreated"}`)
+	now := time.Unix(1_700_000_000, 0)
+	header := Sign("whsec_0123456789abcdef", now, body)
+
+	if !Verify("whsec_0123456789abcdef", header, body, 5*time.Minute, now.Add(time.Minute)) {
+		t.Fatal("Verify() rejected a valid signature")
+	}
+	if Verify("whsec_0123456789abcdef", header, []byte(`{}`), 5*time.Minute, now) {
+		t.Fatal("Verify() accepted a modified body")
+	}
+	if Verify("whsec_0123456789abcdef", header, body, 5*time.Minute, now.Add(time.Hour)) {
+		t.Fatal("Verify() accepted a stale timestamp")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 30 * time.Second},
+		{attempt: 2, want: time.Minute},
+		{attempt: 4, want: 4 * time.Minute},
+		{attempt: 20, want: time.Hour},
+	}
+	for _, tc := range tests {
+		if got := Backoff(tc.attempt, 30*time.Second, time.Hour); got != tc.want {
+			t.Fatalf("Backoff(%d) = %v, want %v", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	const secret = "whsec_0123456789abcdef"
+	payload := []byte(`{"id":"e1","type":"ShipmentCreated"}`)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		attempts   int
+		want       string
+	}{
+		{name: "delivered", statusCode: http.StatusNoContent, attempts: 0, want: domain.DeliveryDelivered},
+		{name: "retried", statusCode: http.StatusInternalServerError, attempts: 2, want: domain.DeliveryPending},
+		{name: "dead lettered", statusCode: http.StatusInternalServerError, attempts: 7, want: domain.DeliveryDead},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if !Verify(secret, r.Header.Get(HeaderSignature), body, time.Minute, time.Now()) {
+					t.Errorf("receiver could not verify signature %q", r.Header.Get(HeaderSignature))
+				}
+				if r.Header.Get(HeaderEventType) != "ShipmentCreated" {
+					t.Errorf("event type header = %q", r.Header.Get(HeaderEventType))
+				}
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			store := &fakeStore{deliveries: []domain.Delivery{{
+				ID: "d1", URL: server.URL, Secret: secret, EventID: "e1",
+				EventType: "ShipmentCreated", Payload: payload, Attempts: tc.attempts,
+			}}}
+			dispatcher := NewDispatcher(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			dispatcher.client = server.Client()
+			dispatcher.dispatch(context.Background())
+
+			if len(store.recorded) != 1 {
+				t.Fatalf("recorded %d attempts, want 1", len(store.recorded))
+			}
+			got := store.recorded[0]
+			if got.status != tc.want {
+				t.Fatalf("status = %s, want %s", got.status, tc.want)
+			}
+			if got.attempt.Number != tc.attempts+1 || got.attempt.StatusCode != tc.statusCode {
+				t.Fatalf("attempt = %+v", got.attempt)
+			}
+			if tc.want == domain.DeliveryPending && !got.next.After(got.attempt.AttemptedAt) {
+				t.Fatalf("next attempt %v not after %v", got.next, got.attempt.AttemptedAt)
+			}
+		})
+	}
+}
+
+func TestDispatchSendsConcurrently(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := &fakeStore{}
+	dispatcher := NewDispatcher(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	dispatcher.client = server.Client()
+	for i := range dispatcher.batchSize {
+		store.deliveries = append(store.deliveries, domain.Delivery{
+			ID: string(rune('a' + i)), URL: server.URL, Secret: "whsec_0123456789abcdef",
+			EventID: "e1", EventType: "ShipmentCreated", Payload: []byte(`{}`),
+		})
+	}
+
+	started := time.Now()
+	dispatcher.dispatch(context.Background())
+	elapsed := time.Since(started)
+
+	if len(store.recorded) != dispatcher.batchSize {
+		t.Fatalf("recorded %d attempts, want %d", len(store.recorded), dispatcher.batchSize)
+	}
+	rounds := (dispatcher.batchSize + dispatcher.workers - 1) / dispatcher.workers
+	if limit := time.Duration(rounds+1) * delay; elapsed > limit {
+		t.Fatalf("dispatch() took %v, want at most %v", elapsed, limit)
+	}
+	if worst := time.Duration(rounds) * dispatcher.client.Timeout; worst >= dispatcher.lease {
+		t.Fatalf("a batch of hanging receivers takes %v, want less than the %v lease", worst, dispatcher.lease)
+	}
+}
+
+func TestDispatchRefusesInternalAddresses(t *testing.T) {
+	reached := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer server.Close()
+
+	store := &fakeStore{deliveries: []domain.Delivery{{
+		ID: "d1", URL: server.URL, Secret: "whsec_0123456789abcdef", EventID: "e1",
+		EventType: "ShipmentCreated", Payload: []byte(`{}`),
+	}}}
+	dispatcher := NewDispatcher(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	dispatcher.dispatch(context.Background())
+
+	if reached {
+		t.Fatal("delivery reached a loopback receiver")
+	}
+	if len(store.recorded) != 1 || !strings.Contains(store.recorded[0].attempt.Error, ErrBlockedAddress.Error()) {
+		t.Fatalf("recorded = %+v, want an attempt failed with %v", store.recorded, ErrBlockedAddress)
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "203.0.113.10", want: true},
+		{addr: "2001:db8::1", want: true},
+		{addr: "127.0.0.1", want: false},
+		{addr: "::1", want: false},
+		{addr: "10.1.2.3", want: false},
+		{addr: "172.16.0.1", want: false},
+		{addr: "192.168.1.1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "fe80::1", want: false},
+		{addr: "fd00::1", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "100.127.255.254", want: false},
+		{addr: "100.128.0.1", want: true},
+		{addr: "0.1.2.3", want: false},
+		{addr: "192.0.0.170", want: false},
+		{addr: "198.18.0.1", want: false},
+		{addr: "198.19.255.254", want: false},
+		{addr: "198.20.0.1", want: true},
+		{addr: "64:ff9b::a9fe:a9fe", want: false},
+		{addr: "2002:a9fe:a9fe::1", want: false},
+		{addr: "255.255.255.255", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := publicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Fatalf("publicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicAddrBlocksEveryPrefix(t *testing.T) {
+	for _, prefix := range blockedPrefixes {
+		t.Run(prefix.String(), func(t *testing.T) {
+			for _, addr := range []netip.Addr{prefix.Masked().Addr(), lastAddr(prefix)} {
+				if publicAddr(addr) {
+					t.Fatalf("publicAddr(%s) = true, want false", addr)
+				}
+			}
+		})
+	}
+}
+
+// lastAddr returns the highest address of prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	bytes := prefix.Masked().Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(bytes)*8; bit++ {
+		bytes[bit/8] |= 0x80 >> (bit % 8)
+	}
+	addr, _ := netip.AddrFromSlice(bytes)
+	return addr
+}
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	shipment "shipment-customer-service/internal/domain/shipment"
+	domain "shipment-customer-service/internal/domain/webhook"
+)
+
+const (
+	minSecretLength   = 16
+	deliveryListLimit = 100
+)
+
+type Repository interface {
+	CreateWebhookSubscription(ctx context.Context, url, secret string, eventTypes []string) (domain.Subscription, error)
+	GetWebhookSubscription(ctx context.Context, id string) (domain.Subscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]domain.Subscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.Delivery, error)
+	GetWebhookDelivery(ctx context.Context, subscriptionID, deliveryID string) (domain.Delivery, []domain.Attempt, error)
+	RedeliverWebhook(ctx context.Context, subscriptionID, deliveryID string) (domain.Delivery, error)
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repository Repository) *Service {
+	return &Service{repo: repository}
+}
+
+// CreateSubscription registers a webhook endpoint. When no secret is given a
+// random one is generated; either way it is only returned from this call.
+func (s *Service) CreateSubscription(ctx context.Context, input domain.CreateSubscriptionInput) (domain.Subscription, error) {
+	target, err := url.Parse(strings.TrimSpace(input.URL))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return domain.Subscription{}, domain.ErrInvalidURL
+	}
+	// Names are only checked when deliveries dial them, see newClient;
+	// internal hosts written out are turned away right here.
+	if host := target.Hostname(); host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return domain.Subscription{}, domain.ErrInvalidURL
+	}
+	if addr, err := netip.ParseAddr(target.Hostname()); err == nil && !publicAddr(addr) {
+		return domain.Subscription{}, domain.ErrInvalidURL
+	}
+
+	if len(input.EventTypes) == 0 {
+		return domain.Subscription{}, domain.ErrInvalidEventType
+	}
+	for _, eventType := range input.EventTypes {
+		if !isKnownEventType(eventType) {
+			return domain.Subscription{}, domain.ErrInvalidEventType
+		}
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		if secret, err = generateSecret(); err != nil {
+			return domain.Subscription{}, err
+		}
+	}
+	if len(secret) < minSecretLength {
+		return domain.Subscription{}, domain.ErrInvalidSecret
+	}
+
+	return s.repo.CreateWebhookSubscription(ctx, target.String(), secret, input.EventTypes)
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context) ([]domain.Subscription, error) {
+	return s.repo.ListWebhookSubscriptions(ctx)
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.ErrInvalidID
+	}
+
+	err := s.repo.DeleteWebhookSubscription(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrSubscriptionNotFound
+	}
+	return err
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, subscriptionID string) ([]domain.Delivery, error) {
+	if _, err := uuid.Parse(subscriptionID); err != nil {
+		return nil, domain.ErrInvalidID
+	}
+
+	_, err := s.repo.GetWebhookSubscription(ctx, subscriptionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListWebhookDeliveries(ctx, subscriptionID, deliveryListLimit)
+}
+
+func (s *Service) GetDelivery(ctx context.Context, subscriptionID, deliveryID string) (domain.Delivery, []domain.Attempt, error) {
+	if !validIDs(subscriptionID, deliveryID) {
+		return domain.Delivery{}, nil, domain.ErrInvalidID
+	}
+
+	delivery, attempts, err := s.repo.GetWebhookDelivery(ctx, subscriptionID, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Delivery{}, nil, domain.ErrDeliveryNotFound
+	}
+	return delivery, attempts, err
+}
+
+// Redeliver queues a delivery to be sent again, including deliveries that
+// already succeeded or were dead-lettered. Attempts are not reset: one that
+// used up its retries is dead-lettered again if the new attempt fails.
+func (s *Service) Redeliver(ctx context.Context, subscriptionID, deliveryID string) (domain.Delivery, error) {
+	if !validIDs(subscriptionID, deliveryID) {
+		return domain.Delivery{}, domain.ErrInvalidID
+	}
+
+	delivery, err := s.repo.RedeliverWebhook(ctx, subscriptionID, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Delivery{}, domain.ErrDeliveryNotFound
+	}
+	return delivery, err
+}
+
+func isKnownEventType(eventType string) bool {
+	switch eventType {
+	case domain.AllEvents, shipment.EventShipmentCreated, shipment.EventStatusChanged, shipment.EventCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func validIDs(ids ...string) bool {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,10 @@
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS erased_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS customer_erasures (
+  seq BIGSERIAL PRIMARY KEY,
+  customer_id UUID NOT NULL REFERENCES customers(id),
+  reason TEXT NOT NULL,
+  erased_at TIMESTAMPTZ NOT NULL,
+  prev_hash TEXT NOT NULL,
+  hash TEXT UNIQUE NOT NULL
+);
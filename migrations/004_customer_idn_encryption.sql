@@ -0,0 +1,8 @@
+ALTER TABLE customers ALTER COLUMN idn DROP NOT NULL;
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS idn_index BYTEA UNIQUE;
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS idn_ciphertext BYTEA;
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS idn_key_id TEXT;
+
+-- Erased customers keep no IDN at all; remaining plaintext values are
+-- encrypted by the customer service on startup and then cleared.
+UPDATE customers SET idn = NULL WHERE erased_at IS NOT NULL;
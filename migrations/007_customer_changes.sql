@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS customer_changes (
+  seq BIGSERIAL PRIMARY KEY,
+  customer_id UUID NOT NULL REFERENCES customers(id),
+  change_type TEXT NOT NULL,
+  merged_into_id UUID REFERENCES customers(id),
+  occurred_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The backfill skips customers already logged, so running it again adds
+-- nothing.
+INSERT INTO customer_changes (customer_id, change_type, occurred_at)
+SELECT c.id, 'CREATED', c.created_at FROM customers c
+WHERE NOT EXISTS (SELECT 1 FROM customer_changes ch WHERE ch.customer_id = c.id AND ch.change_type = 'CREATED')
+ORDER BY c.created_at;
+
+INSERT INTO customer_changes (customer_id, change_type, occurred_at)
+SELECT e.customer_id, 'ERASED', e.erased_at FROM customer_erasures e
+WHERE NOT EXISTS (SELECT 1 FROM customer_changes ch WHERE ch.customer_id = e.customer_id AND ch.change_type = 'ERASED')
+ORDER BY e.seq;
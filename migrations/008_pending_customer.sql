@@ -0,0 +1,8 @@
+ALTER TABLE shipments ALTER COLUMN customer_id DROP NOT NULL;
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS customer_idn TEXT;
+ALTER TABLE shipments ADD CONSTRAINT shipments_customer_present
+  CHECK (customer_id IS NOT NULL OR customer_idn IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS shipments_pending_customer_idx
+  ON shipments (created_at)
+  WHERE status = 'PENDING_CUSTOMER';
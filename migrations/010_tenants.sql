@@ -0,0 +1,79 @@
+-- Every brand's data carries its tenant. Rows written before tenants existed
+-- belong to the default tenant; afterwards the services always set it.
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE customer_changes ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
+
+ALTER TABLE customers ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE customer_changes ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE shipments ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE outbox ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE webhook_subscriptions ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE webhook_deliveries ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- The same person may be a customer of several brands.
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_idn_key;
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_idn_index_key;
+ALTER TABLE customers ADD CONSTRAINT customers_tenant_idn_index_key UNIQUE (tenant_id, idn_index);
+
+-- A shipment can only reference a customer of its own tenant.
+ALTER TABLE customers ADD CONSTRAINT customers_tenant_id_key UNIQUE (tenant_id, id);
+ALTER TABLE shipments ADD CONSTRAINT shipments_tenant_customer_fkey
+  FOREIGN KEY (tenant_id, customer_id) REFERENCES customers (tenant_id, id);
+
+CREATE INDEX IF NOT EXISTS shipments_tenant_created_idx ON shipments (tenant_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS outbox_tenant_idx ON outbox (tenant_id, id);
+
+-- Row-level security is enforced for the tenant_scoped role, which the
+-- services switch to for every request transaction. app.tenant_id holds the
+-- tenant of the transaction, or '*' for cross-tenant background work; when it
+-- is unset no rows are visible at all. api_keys stay outside: a key has to be
+-- found before the tenant of a request is known.
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'tenant_scoped') THEN
+    CREATE ROLE tenant_scoped NOLOGIN;
+  END IF;
+END
+$$;
+GRANT tenant_scoped TO CURRENT_USER;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO tenant_scoped;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO tenant_scoped;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO tenant_scoped;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO tenant_scoped;
+
+ALTER TABLE customers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customers FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customers
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE customer_changes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customer_changes FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customer_changes
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE shipments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE shipments FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON shipments
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE outbox ENABLE ROW LEVEL SECURITY;
+ALTER TABLE outbox FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON outbox
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE webhook_subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_subscriptions
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
+
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_deliveries
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
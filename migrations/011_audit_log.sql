@@ -0,0 +1,41 @@
+-- Who changed what, for compliance reviews. Each tenant's entries form a hash
+-- chain over prev_hash, see internal/platform/audit.
+CREATE TABLE IF NOT EXISTS audit_log (
+  seq BIGSERIAL PRIMARY KEY,
+  tenant_id TEXT NOT NULL,
+  actor TEXT NOT NULL,
+  action TEXT NOT NULL,
+  entity_type TEXT NOT NULL,
+  entity_id TEXT NOT NULL,
+  changes JSONB NOT NULL,
+  request_id TEXT NOT NULL,
+  trace_id TEXT NOT NULL,
+  occurred_at TIMESTAMPTZ NOT NULL,
+  prev_hash TEXT NOT NULL,
+  hash TEXT UNIQUE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_tenant_idx ON audit_log (tenant_id, seq);
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (tenant_id, entity_type, entity_id, seq);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (tenant_id, actor, seq);
+
+-- The log is append-only. The services never get to rewrite it, and the
+-- trigger stops everybody else short of disabling it; the hash chain shows
+-- what happened if someone does.
+REVOKE UPDATE, DELETE, TRUNCATE ON audit_log FROM tenant_scoped;
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+  FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+ALTER TABLE audit_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_log FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON audit_log
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
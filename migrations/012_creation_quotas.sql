@@ -0,0 +1,27 @@
+-- Daily limits on the shipments a client may create. client_id is an API key
+-- ID or token subject; '*' in either column matches every tenant or client,
+-- and the most specific row wins. Clients without a matching row are not
+-- limited. The limits are operator configuration: the services only read them.
+CREATE TABLE IF NOT EXISTS creation_quotas (
+  tenant_id TEXT NOT NULL,
+  client_id TEXT NOT NULL,
+  daily_limit INTEGER NOT NULL CHECK (daily_limit >= 0),
+  PRIMARY KEY (tenant_id, client_id)
+);
+
+REVOKE INSERT, UPDATE, DELETE ON creation_quotas FROM tenant_scoped;
+
+-- Shipments created per client and UTC day, counted in the transaction that
+-- creates them.
+CREATE TABLE IF NOT EXISTS creation_quota_usage (
+  tenant_id TEXT NOT NULL,
+  client_id TEXT NOT NULL,
+  day DATE NOT NULL,
+  used INTEGER NOT NULL,
+  PRIMARY KEY (tenant_id, client_id, day)
+);
+
+ALTER TABLE creation_quota_usage ENABLE ROW LEVEL SECURITY;
+ALTER TABLE creation_quota_usage FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON creation_quota_usage
+  USING (current_setting('app.tenant_id', true) IN (tenant_id, '*'));
@@ -0,0 +1,13 @@
+-- Migrations applied to this database. Every migration from here on records
+-- its own version; the services refuse to report ready against a schema older
+-- than they need, see internal/platform/health.
+CREATE TABLE IF NOT EXISTS schema_migrations (
+  version INTEGER PRIMARY KEY,
+  applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+REVOKE INSERT, UPDATE, DELETE ON schema_migrations FROM tenant_scoped;
+
+INSERT INTO schema_migrations (version)
+SELECT generate_series(1, 13)
+ON CONFLICT (version) DO NOTHING;
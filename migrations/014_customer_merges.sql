@@ -0,0 +1,5 @@
+-- A legacy customer whose IDN turned up again under a new, indexed customer is
+-- merged into it when re-encrypted; see customer_changes for the MERGED entry.
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS merged_into_id UUID REFERENCES customers(id);
+
+INSERT INTO schema_migrations (version) VALUES (14) ON CONFLICT (version) DO NOTHING;
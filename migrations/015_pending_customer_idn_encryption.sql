@@ -0,0 +1,22 @@
+-- Pending shipments keep the customer IDN encrypted with the customer
+-- service's keyring, plus its blind index so that erasing the customer can
+-- find them. Legacy plaintext values are encrypted by shipment-service on
+-- startup and then cleared.
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS customer_idn_index BYTEA;
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS customer_idn_ciphertext BYTEA;
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS customer_idn_key_id TEXT;
+
+CREATE INDEX IF NOT EXISTS shipments_customer_idn_index_idx
+  ON shipments (tenant_id, customer_idn_index)
+  WHERE customer_idn_index IS NOT NULL;
+
+-- Only pending shipments need the IDN; cancelled ones drop it.
+UPDATE shipments SET customer_idn = NULL WHERE status <> 'PENDING_CUSTOMER';
+
+-- A pending shipment loses its IDN when the customer is erased and is then
+-- cancelled, so the IDN can no longer be required.
+ALTER TABLE shipments DROP CONSTRAINT IF EXISTS shipments_customer_present;
+ALTER TABLE shipments ADD CONSTRAINT shipments_customer_present
+  CHECK (customer_id IS NOT NULL OR status IN ('PENDING_CUSTOMER', 'CANCELLED'));
+
+INSERT INTO schema_migrations (version) VALUES (15) ON CONFLICT (version) DO NOTHING;
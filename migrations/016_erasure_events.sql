@@ -0,0 +1,15 @@
+-- Erasures reach shipment-service through the customer change stream. The
+-- ERASED entry keeps the erased IDN, encrypted with the customer keyring, so
+-- that consumers can drop their own copies; customer-service clears it once
+-- ERASED_IDN_RETENTION has passed.
+ALTER TABLE customer_changes ADD COLUMN IF NOT EXISTS idn_ciphertext BYTEA;
+ALTER TABLE customer_changes ADD COLUMN IF NOT EXISTS idn_key_id TEXT;
+
+-- How far shipment-service has followed the customer change stream, so that
+-- erasures made while it was down still reach its pending shipments.
+CREATE TABLE IF NOT EXISTS customer_change_cursor (
+  id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+  seq BIGINT NOT NULL
+);
+
+INSERT INTO schema_migrations (version) VALUES (16) ON CONFLICT (version) DO NOTHING;
@@ -6,6 +6,8 @@ BASE_URL="${BASE_URL:-http://localhost:8080}"
 IDN="${IDN:-990101123456}"
 ROUTE="${ROUTE:-ALMATY->ASTANA}"
 PRICE="${PRICE:-120000}"
+API_KEY="${API_KEY:-$(sed -n 's/^API_KEY_BOOTSTRAP=//p' "$(dirname "${COMPOSE_FILE}")/.env" 2>/dev/null || true)}"
+[[ -n "${API_KEY}" ]] || { echo "FAIL: set API_KEY or run make run first"; exit 1; }
 
 TMP_DIR="$(mktemp -d)"
 trap 'rm -rf "$TMP_DIR"' EXIT
@@ -27,7 +29,7 @@ extract_json_value() {
 
 payload="{\"route\":\"${ROUTE}\",\"price\":${PRICE},\"customer\":{\"idn\":\"${IDN}\"}}"
 
-post_code="$(curl -s -o "${TMP_DIR}/post.json" -w "%{http_code}" -X POST "${BASE_URL}/api/v1/shipments" -H "Content-Type: application/json" -d "${payload}" || true)"
+post_code="$(curl -s -o "${TMP_DIR}/post.json" -w "%{http_code}" -X POST "${BASE_URL}/api/v1/shipments" -H "X-API-Key: ${API_KEY}" -H "Content-Type: application/json" -d "${payload}" || true)"
 [[ "${post_code}" == "201" ]] || fail "POST returned ${post_code}"
 shipment_id="$(extract_json_value "${TMP_DIR}/post.json" "id")"
 customer_id="$(extract_json_value "${TMP_DIR}/post.json" "customerId")"
@@ -47,7 +49,7 @@ else
   pass "grpc port 9090 is not exposed outside"
 fi
 
-get_code="$(curl -s -o "${TMP_DIR}/get.json" -w "%{http_code}" "${BASE_URL}/api/v1/shipments/${shipment_id}" || true)"
+get_code="$(curl -s -o "${TMP_DIR}/get.json" -w "%{http_code}" -H "X-API-Key: ${API_KEY}" "${BASE_URL}/api/v1/shipments/${shipment_id}" || true)"
 [[ "${get_code}" == "200" ]] || fail "GET returned ${get_code}"
 grep -q "\"id\":\"${shipment_id}\"" "${TMP_DIR}/get.json" || fail "GET response id mismatch"
 grep -q "\"customerId\":\"${customer_id}\"" "${TMP_DIR}/get.json" || fail "GET response customer id mismatch"